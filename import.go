@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/importer"
+)
+
+// runImport implements the import subcommand, converting a conversation
+// from another tool into a session of a project. It returns the exit status.
+func runImport(args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), `Usage:
+  claude-session-browser import [options] FILE
+
+Converts a conversation from another tool into a Claude session file, so it
+shows up in the browser. FILE may be - to read stdin.
+
+Formats:
+  json    A JSON array of {"role": "user"|"assistant", "content": "..."}
+          objects, each with an optional RFC 3339 "timestamp"
+
+Options:
+`)
+		fs.PrintDefaults()
+	}
+
+	var claudeDir string
+	fs.StringVar(&claudeDir, "claude-dir", "", "Claude projects directory (default: ~/.claude/projects)")
+	fs.StringVar(&claudeDir, "d", "", "Claude projects directory (shorthand)")
+	format := fs.String("format", "json", "Input format: "+strings.Join(importer.Formats(), ", "))
+	project := fs.String("project", "", "Project directory the session belongs to (default: current directory)")
+	sessionID := fs.String("id", "", "Session ID to use (default: a new random one)")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	input, err := importer.Lookup(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	cwd := *project
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+	cwd, err = filepath.Abs(cwd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to resolve project directory:", err)
+		return 1
+	}
+	projectDir := filepath.Join(resolveClaudeDir(claudeDir), convertToClaudePath(cwd))
+
+	var src io.Reader = os.Stdin
+	if name := fs.Arg(0); name != "-" {
+		file, err := os.Open(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		src = file
+	}
+
+	path, err := importer.Import(src, input, projectDir, importer.Options{SessionID: *sessionID, Cwd: cwd})
+	if err != nil && path == "" {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Warning:", err)
+	}
+	fmt.Println(path)
+	return 0
+}
@@ -0,0 +1,53 @@
+// Package claudeconfig reads the sessions Claude Code itself keeps track
+// of from its user config file (~/.claude.json)
+package claudeconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// DefaultPath returns where Claude Code keeps its user config:
+// $CLAUDE_CONFIG_DIR/.claude.json when that is set, else ~/.claude.json
+func DefaultPath() string {
+	if dir := os.Getenv("CLAUDE_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, ".claude.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude.json")
+}
+
+// ActiveSessions returns the IDs of the sessions the config at path names
+// as a project's last session, the one `claude --continue` resumes. A
+// missing, unreadable or unexpected file gives none, as does any project
+// entry in an unexpected shape, without affecting the others.
+func ActiveSessions(path string) map[string]bool {
+	active := make(map[string]bool)
+	if path == "" {
+		return active
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return active
+	}
+	var config struct {
+		Projects map[string]json.RawMessage `json:"projects"`
+	}
+	if json.Unmarshal(data, &config) != nil {
+		return active
+	}
+	for _, raw := range config.Projects {
+		var project struct {
+			LastSessionID string `json:"lastSessionId"`
+		}
+		if json.Unmarshal(raw, &project) == nil && project.LastSessionID != "" {
+			active[project.LastSessionID] = true
+		}
+	}
+	return active
+}
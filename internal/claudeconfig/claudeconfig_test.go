@@ -0,0 +1,43 @@
+package claudeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestActiveSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claude.json")
+	content := `{
+  "numStartups": 12,
+  "projects": {
+    "/work/api": {"lastSessionId": "a1b2", "allowedTools": []},
+    "/work/web": {"lastCost": 0.5},
+    "/work/odd": "not an object",
+    "/work/cli": {"lastSessionId": "c3d4"}
+  }
+}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	active := ActiveSessions(path)
+	if len(active) != 2 || !active["a1b2"] || !active["c3d4"] {
+		t.Errorf("Expected a1b2 and c3d4, got %v", active)
+	}
+}
+
+// Absent or malformed configs mark nothing rather than failing
+func TestActiveSessionsUnexpectedFiles(t *testing.T) {
+	dir := t.TempDir()
+	malformed := filepath.Join(dir, "malformed.json")
+	if err := os.WriteFile(malformed, []byte(`{"projects": [1, 2]}`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	for _, path := range []string{"", filepath.Join(dir, "missing.json"), malformed} {
+		if active := ActiveSessions(path); len(active) != 0 {
+			t.Errorf("%q: expected no active sessions, got %v", path, active)
+		}
+	}
+}
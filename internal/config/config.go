@@ -0,0 +1,397 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Config holds user preferences loaded from the config file.
+// Values in the file override the defaults; missing keys keep them.
+type Config struct {
+	Search     SearchConfig     `json:"search"`
+	List       ListConfig       `json:"list"`
+	Share      ShareConfig      `json:"share"`
+	Status     StatusConfig     `json:"status"`
+	Fields     FieldsConfig     `json:"fields"`
+	Projects   ProjectsConfig   `json:"projects"`
+	Copy       CopyConfig       `json:"copy"`
+	Summary    SummaryConfig    `json:"summary"`
+	Pick       PickConfig       `json:"pick"`
+	Pipe       PipeConfig       `json:"pipe"`
+	Theme      ThemeConfig      `json:"theme"`
+	Transcript TranscriptConfig `json:"transcript"`
+	Cost       CostConfig       `json:"cost"`
+	Layout     LayoutConfig     `json:"layout"`
+	Details    DetailsConfig    `json:"details"`
+	Idle       IdleConfig       `json:"idle"`
+}
+
+// SearchConfig controls content search defaults
+type SearchConfig struct {
+	// IgnoreCase is the startup case sensitivity for content search.
+	// The in-app toggle overrides it for the rest of the run but is
+	// never written back to the file.
+	IgnoreCase bool `json:"ignoreCase"`
+	// Recent limits content search to the N most recently active sessions
+	// at startup; 0 searches everything. Ctrl+R toggles the limit in the
+	// app, and --search-recent overrides this value.
+	Recent int `json:"recent"`
+	// HistorySize is how many past queries are kept for recall with the
+	// arrow keys; 0 turns the history file off
+	HistorySize int `json:"historySize"`
+	// Backend is the content search program: "rg" (ripgrep), "go" (built
+	// in, no external process) or "auto" (ripgrep when installed).
+	// --search-backend overrides it.
+	Backend string `json:"backend"`
+	// Index keeps every listed session's content in memory once the list
+	// loads, so searches read neither files nor start ripgrep; files that
+	// changed are read again. --no-index turns it off.
+	Index bool `json:"index"`
+	// Enter is what Enter does in the search box: "results" moves to the
+	// results, "copy" also copies the top result's resume command. Tab
+	// always just moves to the results.
+	Enter string `json:"enter"`
+}
+
+// ListConfig controls the session list
+type ListConfig struct {
+	// Display is the label shown per session: "id", "title" or "preview".
+	// Press t in the app to cycle through them.
+	Display string `json:"display"`
+	// Sort is the list order: "recent", "title" (alphabetical, untitled
+	// last), "cost" or "messages" (highest first), or "viewed" (most
+	// recently opened in the browser first). Press o in the app to cycle.
+	Sort string `json:"sort"`
+	// ThenSort orders sessions that tie on Sort, taking the same values.
+	// Remaining ties go by session ID so the order never changes between
+	// refreshes.
+	ThenSort string `json:"thenSort"`
+	// TitleMessages is how many of a session's first user messages make up
+	// its title when it has none of its own, for sessions that open with a
+	// greeting before the real request
+	TitleMessages int `json:"titleMessages"`
+	// PreviewDelayMs waits this long after the selection stops moving
+	// before loading the session details. 0 loads immediately.
+	PreviewDelayMs int `json:"previewDelayMs"`
+	// HideEmpty hides sessions without any user or assistant messages,
+	// such as files holding only a summary. Press e in the app to toggle.
+	HideEmpty bool `json:"hideEmpty"`
+	// ShowSize adds each session file's size to the list. Press s in the
+	// app to toggle.
+	ShowSize bool `json:"showSize"`
+	// ShowCount adds each session's message count to the list, as counted
+	// by the listing scan. Toggle it from the command palette.
+	ShowCount bool `json:"showCount"`
+	// Wrap continues titles and previews too long for their row on a
+	// second line instead of truncating them. Toggle it from the command
+	// palette.
+	Wrap bool `json:"wrap"`
+	// ActiveFirst lists the sessions Claude Code's user config names as a
+	// project's last session (★ in the list) before the rest, whatever the
+	// sort order. Toggle it from the command palette.
+	ActiveFirst bool `json:"activeFirst"`
+	// MergeContinuations lists a conversation split across several files
+	// as one session. A file continues another when the session ID
+	// recorded inside it (fields.sessionId) is the other file's ID.
+	MergeContinuations bool `json:"mergeContinuations"`
+	// ShortIDs shows IDs as their first group ("a1b2c3d4…") in the list.
+	// Copying and resuming always use the full ID.
+	ShortIDs bool `json:"shortIds"`
+	// RefreshOnFocus reloads the list when the terminal window regains
+	// focus. Terminals that do not report focus never trigger it; r always
+	// refreshes by hand.
+	RefreshOnFocus bool `json:"refreshOnFocus"`
+	// RepoOnly starts on every session run inside the git repository the
+	// browser was started in, across projects, instead of the detected
+	// project. Press ctrl+g in the app to toggle.
+	RepoOnly bool `json:"repoOnly"`
+	// AllProjects starts on the sessions of every project merged into one
+	// list, each row naming its project, instead of just the detected
+	// project. Press A in the app to toggle; repoOnly wins when both apply.
+	AllProjects bool `json:"allProjects"`
+}
+
+// ShareConfig controls the "open in web" export. Nothing is uploaded
+// unless Backend is "gist" and a token is set.
+type ShareConfig struct {
+	// Backend is "file" (write locally) or "gist" (upload to GitHub)
+	Backend string `json:"backend"`
+	// Format is "html" or "markdown"; empty picks HTML for files and
+	// Markdown for gists, which GitHub renders
+	Format string `json:"format"`
+	// OutputDir is where the file backend writes (default: system temp dir)
+	OutputDir string `json:"outputDir"`
+	// GitHubToken needs the gist scope
+	GitHubToken string `json:"githubToken"`
+	// Public makes uploaded gists public instead of secret
+	Public bool `json:"public"`
+}
+
+// StatusConfig controls the status bar
+type StatusConfig struct {
+	// DurationMs is how long status messages stay visible. Copy
+	// confirmations and warnings keep their own shorter/longer durations.
+	DurationMs int `json:"durationMs"`
+}
+
+// ProjectsConfig controls the project picker and all-projects table
+type ProjectsConfig struct {
+	// HomeOnly hides projects whose decoded path is outside $HOME.
+	// Toggle with ctrl+o in the picker or h in the table.
+	HomeOnly bool `json:"homeOnly"`
+	// Exclude leaves projects out of the picker, the all-projects table and
+	// searches across projects: project paths such as "~/scratch" (which
+	// also covers the projects under it), globs such as "/tmp/*", or
+	// encoded directory names. Projects listed in
+	// .session-browser-ignore-projects in the projects root are left out
+	// too.
+	Exclude []string `json:"exclude"`
+}
+
+// CopyConfig controls what Enter puts on the clipboard
+type CopyConfig struct {
+	// TrailingNewline ends the copied resume command with a newline. Many
+	// terminals run pasted text that ends in one, so it is off by default.
+	TrailingNewline bool `json:"trailingNewline"`
+	// RawJSON copies the last message exactly as stored on one line instead
+	// of indenting it. Press J in the app to copy.
+	RawJSON bool `json:"rawJson"`
+	// Feedback is how a copy is confirmed: "timed" (a status message for
+	// two seconds), "instant" (no message) or "badge" (a "copied ✓" mark
+	// on the copied session's row until the next copy)
+	Feedback string `json:"feedback"`
+	// LinkTemplate builds the link L copies, filling in {id}, {project},
+	// {cwd} and {path}; the default links to the session file
+	LinkTemplate string `json:"linkTemplate"`
+	// CompactTemplate builds the resume command C copies, with the same
+	// placeholders; the default resumes and asks Claude to compact at once
+	CompactTemplate string `json:"compactTemplate"`
+	// MatchTemplate builds the resume command Enter copies while a search
+	// match is listed in the details, adding {message} for the matched
+	// message's UUID; empty (the default) always copies the plain resume
+	MatchTemplate string `json:"matchTemplate"`
+}
+
+// PickConfig controls --pick mode
+type PickConfig struct {
+	// Output is what is printed for the chosen session: "id" or "path"
+	// (the session file). --pick-output overrides it.
+	Output string `json:"output"`
+}
+
+// PipeConfig controls the | action, which feeds the selected session to an
+// external command such as a pager or summarizer
+type PipeConfig struct {
+	// Command is split on spaces, without shell quoting, and run with the
+	// session on stdin; --pipe-command overrides it
+	Command string `json:"command"`
+	// Raw sends the session's JSONL file instead of a plain-text transcript
+	Raw bool `json:"raw"`
+}
+
+// TranscriptConfig controls the transcript viewer
+type TranscriptConfig struct {
+	// CollapseTools folds each run of tool calls and tool results into a
+	// single line that Enter expands
+	CollapseTools bool `json:"collapseTools"`
+}
+
+// CostConfig controls where session costs are shown
+type CostConfig struct {
+	// Display is "auto" (hidden when no listed session recorded a cost),
+	// "show" or "hide". It covers the details pane and the cost column of
+	// the all-projects table; --hide-cost sets "hide".
+	Display string `json:"display"`
+}
+
+// LayoutConfig controls how the main view is split into panes
+type LayoutConfig struct {
+	// SidebarMinWidth adds a third pane with token, cost and file stats
+	// when the terminal is wider than this many columns; 0 never shows it
+	SidebarMinWidth int `json:"sidebarMinWidth"`
+}
+
+// DetailsConfig controls the details pane
+type DetailsConfig struct {
+	// RawMessages is how many of the session's last JSON lines x shows in
+	// place of only the last one
+	RawMessages int `json:"rawMessages"`
+	// View is what the pane shows when not on the raw JSON view X opens:
+	// "both" for the session details with its raw JSON below, or "parsed"
+	// for the details alone
+	View string `json:"view"`
+	// ShowPosition shows where the selected session is in the list, as
+	// "Session 3 of 42", beside the pane's title
+	ShowPosition bool `json:"showPosition"`
+}
+
+// IdleConfig controls quitting a browser left open and unused
+type IdleConfig struct {
+	// QuitAfterMinutes quits after this many minutes without a key press;
+	// 0 never quits
+	QuitAfterMinutes int `json:"quitAfterMinutes"`
+}
+
+// ThemeConfig sets the colors used to highlight search matches. Colors are
+// hex ("#FBBF24") or ANSI numbers ("11").
+type ThemeConfig struct {
+	// Highlights colors the matches of each search term in turn
+	Highlights []string `json:"highlights"`
+	// UserHighlight and AssistantHighlight, when set, color transcript
+	// matches by the role of the message they are in instead
+	UserHighlight      string `json:"userHighlight"`
+	AssistantHighlight string `json:"assistantHighlight"`
+	// UserText, AssistantText and ToolText recolor the text of transcript
+	// messages; empty keeps the stock colors (user green, assistant plain,
+	// tool calls and results muted)
+	UserText      string `json:"userText"`
+	AssistantText string `json:"assistantText"`
+	ToolText      string `json:"toolText"`
+	// Border outlines the panes: "rounded", "normal", "thick", "double"
+	// or "none"
+	Border string `json:"border"`
+	// Ellipsis marks text cut to fit, such as "…" to spend one column
+	// instead of three; empty keeps "..."
+	Ellipsis string `json:"ellipsis"`
+	// MatchColors color the [N] match counts in the list by how many
+	// matches a session has, from the fewest to the most; empty leaves them
+	// plain
+	MatchColors []string `json:"matchColors"`
+}
+
+// SummaryConfig shapes the details-pane summary built from the last user
+// messages of sessions that have no summary line. It mirrors
+// parser.SummaryOptions field for field.
+type SummaryConfig struct {
+	// MessageLength is how many characters of each message are kept
+	MessageLength int `json:"messageLength"`
+	// Messages is how many of the last user messages are joined
+	Messages int `json:"messages"`
+	// Separator goes between the messages
+	Separator string `json:"separator"`
+	// KeepNewlines keeps each message's line breaks and runs of spaces
+	// instead of collapsing them to single spaces
+	KeepNewlines bool `json:"keepNewlines"`
+}
+
+// FieldsConfig maps the data the parser reads to the JSON keys used in
+// session files, for forks of Claude Code that name them differently.
+// Content, Model and Usage are nested: entry[Message][Content].
+// It mirrors parser.Fields field for field so one converts to the other.
+type FieldsConfig struct {
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	Cost      string `json:"cost"`
+	Message   string `json:"message"`
+	Content   string `json:"content"`
+	Model     string `json:"model"`
+	Usage     string `json:"usage"`
+	Cwd       string `json:"cwd"`
+	Title     string `json:"title"`
+	SessionID string `json:"sessionId"`
+}
+
+// Default returns the built-in configuration
+func Default() *Config {
+	return &Config{
+		Search: SearchConfig{
+			IgnoreCase:  true,
+			HistorySize: 100,
+			Backend:     "auto",
+			Index:       true,
+			Enter:       "results",
+		},
+		List: ListConfig{
+			Display:        "id",
+			Sort:           "recent",
+			ThenSort:       "recent",
+			TitleMessages:  1,
+			RefreshOnFocus: true,
+		},
+		Share: ShareConfig{
+			Backend: "file",
+		},
+		Status: StatusConfig{
+			DurationMs: 3000,
+		},
+		Copy: CopyConfig{
+			Feedback:        "timed",
+			LinkTemplate:    "file://{path}",
+			CompactTemplate: `claude --resume {id} "/compact"`,
+		},
+		Pick: PickConfig{
+			Output: "id",
+		},
+		Transcript: TranscriptConfig{
+			CollapseTools: true,
+		},
+		Cost: CostConfig{
+			Display: "auto",
+		},
+		Layout: LayoutConfig{
+			SidebarMinWidth: 160,
+		},
+		Details: DetailsConfig{
+			RawMessages:  3,
+			View:         "both",
+			ShowPosition: true,
+		},
+		Theme: ThemeConfig{
+			Highlights:  []string{"#FBBF24", "#22D3EE"},
+			Border:      "rounded",
+			Ellipsis:    "...",
+			MatchColors: []string{"#6B7280", "#9CA3AF", "#FBBF24", "#F97316"},
+		},
+		Summary: SummaryConfig{
+			MessageLength: 150,
+			Messages:      3,
+			Separator:     " | ",
+		},
+		Fields: FieldsConfig{
+			Type:      "type",
+			Timestamp: "timestamp",
+			Cost:      "costUSD",
+			Message:   "message",
+			Content:   "content",
+			Model:     "model",
+			Usage:     "usage",
+			Cwd:       "cwd",
+			Title:     "customTitle",
+			SessionID: "sessionId",
+		},
+	}
+}
+
+// DefaultPath returns the default config file location
+// (e.g. ~/.config/claude-session-browser/config.json on Linux)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-session-browser", "config.json")
+}
+
+// Load reads the config file at path on top of the defaults.
+// A missing file is not an error.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,173 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// Markdown renders a session transcript as a Markdown document
+func Markdown(session *model.FullSession, messages []model.Message) string {
+	var b strings.Builder
+	writeMarkdown(&b, session, messages, "#")
+	return b.String()
+}
+
+// writeMarkdown writes one session with its title at the given heading
+// level and its messages one level below
+func writeMarkdown(b *strings.Builder, session *model.FullSession, messages []model.Message, level string) {
+	fmt.Fprintf(b, "%s Session %s\n\n", level, session.ID)
+	if session.Summary != "" {
+		fmt.Fprintf(b, "> %s\n\n", session.Summary)
+	}
+	fmt.Fprintf(b, "- **ID:** `%s`\n", session.ID)
+	if !session.LastActive.IsZero() {
+		fmt.Fprintf(b, "- **Last active:** %s\n", session.LastActive.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(b, "- **Messages:** %d\n", session.MessageCount)
+	fmt.Fprintf(b, "- **Cost:** $%.4f\n", session.TotalCostUSD)
+	fmt.Fprintf(b, "- **Resume:** `%s`\n", session.GetResumeCommand())
+
+	for _, msg := range messages {
+		fmt.Fprintf(b, "\n%s# %s\n\n", level, messageHeading(msg))
+		b.WriteString(msg.Content)
+		b.WriteString("\n")
+	}
+}
+
+// CombinedMarkdown renders several sessions into one Markdown document,
+// oldest first so the report reads in the order the work happened, each
+// under its own heading. messages[i] holds the turns of sessions[i].
+func CombinedMarkdown(sessions []model.FullSession, messages [][]model.Message) string {
+	order := make([]int, len(sessions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sessions[order[a]].LastActive.Before(sessions[order[b]].LastActive)
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %d sessions\n", len(sessions))
+	for _, i := range order {
+		b.WriteString("\n---\n\n")
+		writeMarkdown(&b, &sessions[i], messages[i], "##")
+	}
+	return b.String()
+}
+
+// Text renders a session transcript as plain text, one headed block per
+// message, for piping into other tools
+func Text(messages []model.Message) string {
+	var b strings.Builder
+	for i, msg := range messages {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n\n%s\n", messageHeading(msg), msg.Content)
+	}
+	return b.String()
+}
+
+// Summary renders a compact Markdown block describing a session, for
+// pasting into an issue or pull request. title falls back to the ID.
+func Summary(title string, session *model.FullSession) string {
+	var b strings.Builder
+
+	if title == "" {
+		title = session.ID
+	}
+	fmt.Fprintf(&b, "**%s**\n\n", title)
+	fmt.Fprintf(&b, "- **Session:** `%s`\n", session.ID)
+	if session.Model != "" {
+		fmt.Fprintf(&b, "- **Model:** %s\n", session.Model)
+	}
+	fmt.Fprintf(&b, "- **Cost:** $%.4f\n", session.TotalCostUSD)
+	if tokens := session.Tokens; tokens.Total() > 0 {
+		fmt.Fprintf(&b, "- **Tokens:** %d in, %d out", tokens.Input, tokens.Output)
+		if cached := tokens.CacheCreation + tokens.CacheRead; cached > 0 {
+			fmt.Fprintf(&b, ", %d cached", cached)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "- **Resume:** `%s`\n", session.GetResumeCommand())
+	if session.Summary != "" {
+		fmt.Fprintf(&b, "\n> %s\n", session.Summary)
+	}
+
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("session").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Session {{.Session.ID}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; max-width: 860px; margin: 2rem auto; padding: 0 1rem; color: #1F2937; }
+h1 { color: #7C3AED; font-size: 1.4rem; }
+dl { display: grid; grid-template-columns: max-content auto; gap: .25rem 1rem; color: #6B7280; }
+dt { font-weight: 600; }
+.message { border-left: 3px solid #E5E7EB; margin: 1.5rem 0; padding-left: 1rem; }
+.message.user { border-color: #10B981; }
+.message.assistant { border-color: #7C3AED; }
+.message h2 { font-size: .9rem; color: #6B7280; margin: 0 0 .5rem; }
+.message pre { white-space: pre-wrap; word-wrap: break-word; font-family: inherit; margin: 0; }
+</style>
+</head>
+<body>
+<h1>Session {{.Session.ID}}</h1>
+{{if .Session.Summary}}<p>{{.Session.Summary}}</p>{{end}}
+<dl>
+<dt>Messages</dt><dd>{{.Session.MessageCount}}</dd>
+<dt>Cost</dt><dd>{{printf "$%.4f" .Session.TotalCostUSD}}</dd>
+<dt>Resume</dt><dd><code>{{.Session.GetResumeCommand}}</code></dd>
+</dl>
+{{range .Messages}}<div class="message {{.Role}}">
+<h2>{{.Heading}}</h2>
+<pre>{{.Content}}</pre>
+</div>
+{{end}}</body>
+</html>
+`))
+
+type htmlMessage struct {
+	Role    string
+	Heading string
+	Content string
+}
+
+// HTML renders a session transcript as a standalone HTML page
+func HTML(session *model.FullSession, messages []model.Message) (string, error) {
+	data := struct {
+		Session  *model.FullSession
+		Messages []htmlMessage
+	}{Session: session}
+
+	for _, msg := range messages {
+		data.Messages = append(data.Messages, htmlMessage{
+			Role:    msg.Role,
+			Heading: messageHeading(msg),
+			Content: msg.Content,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// messageHeading labels a message with its role and time
+func messageHeading(msg model.Message) string {
+	heading := msg.RoleTitle()
+	if !msg.Timestamp.IsZero() {
+		heading += " · " + msg.Timestamp.Local().Format("2006-01-02 15:04")
+	}
+	return heading
+}
@@ -0,0 +1,112 @@
+// Package gitrepo finds the git repository a directory belongs to, reading
+// the .git layout directly instead of running git
+package gitrepo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Repo identifies a git repository. Worktrees of one repository share its
+// CommonDir, so they count as the same Repo.
+type Repo struct {
+	Root      string // top of the working tree dir was found in
+	CommonDir string // the repository's shared .git directory
+}
+
+// Find walks up from dir to the nearest working tree. It reports false when
+// dir is not inside one.
+func Find(dir string) (Repo, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Repo{}, false
+	}
+	for {
+		if common, ok := commonDir(filepath.Join(dir, ".git")); ok {
+			return Repo{Root: dir, CommonDir: common}, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Repo{}, false
+		}
+		dir = parent
+	}
+}
+
+// commonDir resolves a .git entry to the repository's shared git
+// directory. A linked worktree or submodule has a .git file pointing at its
+// own git directory, which in turn names the shared one in "commondir".
+func commonDir(dotGit string) (string, bool) {
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return "", false
+	}
+	if info.IsDir() {
+		return filepath.Clean(dotGit), true
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return "", false
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", false
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(dotGit), gitDir)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		common := strings.TrimSpace(string(data))
+		if !filepath.IsAbs(common) {
+			common = filepath.Join(gitDir, common)
+		}
+		return filepath.Clean(common), true
+	}
+	return filepath.Clean(gitDir), true
+}
+
+// Matcher tells whether directories belong to one repository, remembering
+// the answer for each directory it has looked up
+type Matcher struct {
+	repo  Repo
+	cache map[string]bool
+}
+
+// NewMatcher matches directories against repo
+func NewMatcher(repo Repo) *Matcher {
+	return &Matcher{repo: repo, cache: make(map[string]bool)}
+}
+
+// Contains reports whether dir is inside the repository: within its working
+// tree, or within any other worktree of it. Directories that no longer
+// exist only match by path.
+func (m *Matcher) Contains(dir string) bool {
+	if dir == "" {
+		return false
+	}
+	if contains, ok := m.cache[dir]; ok {
+		return contains
+	}
+
+	contains := isWithin(dir, m.repo.Root)
+	if !contains {
+		if repo, ok := Find(dir); ok {
+			contains = repo.CommonDir == m.repo.CommonDir
+		}
+	}
+	m.cache[dir] = contains
+	return contains
+}
+
+// isWithin reports whether path is root or below it
+func isWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
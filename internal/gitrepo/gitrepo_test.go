@@ -0,0 +1,70 @@
+package gitrepo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func mkdir(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindFromSubdirectory(t *testing.T) {
+	root := t.TempDir()
+	mkdir(t, filepath.Join(root, ".git"))
+	mkdir(t, filepath.Join(root, "services", "api"))
+
+	repo, ok := Find(filepath.Join(root, "services", "api"))
+	if !ok {
+		t.Fatal("Expected to find the repository")
+	}
+	if repo.Root != root || repo.CommonDir != filepath.Join(root, ".git") {
+		t.Errorf("Unexpected repo %+v", repo)
+	}
+
+	if _, ok := Find(t.TempDir()); ok {
+		t.Error("Expected no repository outside one")
+	}
+}
+
+// A linked worktree belongs to the repository it was added from
+func TestMatcherWorktree(t *testing.T) {
+	base := t.TempDir()
+	main := filepath.Join(base, "app")
+	worktree := filepath.Join(base, "app-feature")
+	gitDir := filepath.Join(main, ".git", "worktrees", "app-feature")
+	mkdir(t, gitDir)
+	mkdir(t, filepath.Join(worktree, "src"))
+	if err := os.WriteFile(filepath.Join(worktree, ".git"), []byte("gitdir: "+gitDir+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(base, "other")
+	mkdir(t, filepath.Join(other, ".git"))
+
+	repo, ok := Find(main)
+	if !ok {
+		t.Fatal("Expected to find the repository")
+	}
+	matcher := NewMatcher(repo)
+
+	tests := map[string]bool{
+		main:                               true,
+		filepath.Join(main, "gone", "dir"): true, // removed since, matched by path
+		filepath.Join(worktree, "src"):     true,
+		other:                              false,
+		base:                               false,
+		"":                                 false,
+	}
+	for dir, want := range tests {
+		if got := matcher.Contains(dir); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", dir, got, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// History is a persistent list of search queries, oldest first
+type History struct {
+	path    string
+	max     int
+	entries []string
+}
+
+// DefaultPath returns the default history file location, next to the
+// config file (e.g. ~/.config/claude-session-browser/search_history)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-session-browser", "search_history")
+}
+
+// Load reads the history at path, keeping at most max entries. A missing or
+// unreadable file gives an empty history. With an empty path or max <= 0
+// nothing is kept or written.
+func Load(path string, max int) *History {
+	h := &History{path: path, max: max}
+	if path == "" || max <= 0 {
+		return h
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.trim()
+	return h
+}
+
+// Entries returns the queries, oldest first
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Add records a query and saves the history. Blank queries and repeats of
+// the most recent query are ignored.
+func (h *History) Add(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" || h.path == "" || h.max <= 0 {
+		return nil
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == query {
+		return nil
+	}
+
+	h.entries = append(h.entries, query)
+	h.trim()
+	return h.save()
+}
+
+// trim drops the oldest entries beyond max
+func (h *History) trim() {
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+}
+
+func (h *History) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	content := strings.Join(h.entries, "\n") + "\n"
+	return os.WriteFile(h.path, []byte(content), 0644)
+}
@@ -0,0 +1,46 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAddDedupesAndCaps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "search_history")
+	h := Load(path, 3)
+
+	for _, query := range []string{"oauth", "oauth", "  ", "retry", "oauth", "cache"} {
+		if err := h.Add(query); err != nil {
+			t.Fatalf("Add(%q) failed: %v", query, err)
+		}
+	}
+
+	want := []string{"retry", "oauth", "cache"}
+	if got := h.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Entries survive a reload
+	if got := Load(path, 3).Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected reloaded %v, got %v", want, got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	h := Load(filepath.Join(t.TempDir(), "missing"), 10)
+	if len(h.Entries()) != 0 {
+		t.Errorf("Expected empty history, got %v", h.Entries())
+	}
+}
+
+func TestDisabledHistoryWritesNothing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search_history")
+	h := Load(path, 0)
+	if err := h.Add("oauth"); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if len(Load(path, 10).Entries()) != 0 {
+		t.Error("Expected nothing written when history is disabled")
+	}
+}
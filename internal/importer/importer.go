@@ -0,0 +1,221 @@
+// Package importer converts conversations from other tools into session
+// files the browser (and claude --resume) can read
+package importer
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Message is one turn of an imported conversation
+type Message struct {
+	Role    string // "user" or "assistant"
+	Content string
+	// Timestamp is when the turn happened; zero when the source has none
+	Timestamp time.Time
+}
+
+// Format reads conversations in one input format
+type Format interface {
+	// Parse reads a whole conversation, oldest message first
+	Parse(r io.Reader) ([]Message, error)
+}
+
+// formats holds every input format by the name used with --format
+var formats = map[string]Format{
+	"json": jsonFormat{},
+}
+
+// Register adds an input format under name, replacing any of that name
+func Register(name string, format Format) {
+	formats[name] = format
+}
+
+// Formats returns the names of the registered input formats, sorted
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the input format registered under name
+func Lookup(name string) (Format, error) {
+	format, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown import format %q: want %s", name, strings.Join(Formats(), ", "))
+	}
+	return format, nil
+}
+
+// jsonFormat reads a JSON array of {"role": ..., "content": ...} objects,
+// with an optional RFC 3339 "timestamp" on each
+type jsonFormat struct{}
+
+func (jsonFormat) Parse(r io.Reader) ([]Message, error) {
+	var entries []struct {
+		Role      string    `json:"role"`
+		Content   string    `json:"content"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("reading JSON messages: %w", err)
+	}
+
+	messages := make([]Message, 0, len(entries))
+	for i, entry := range entries {
+		role, err := normalizeRole(entry.Role)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		messages = append(messages, Message{Role: role, Content: entry.Content, Timestamp: entry.Timestamp})
+	}
+	return messages, nil
+}
+
+// normalizeRole maps the role names other assistants use onto Claude's.
+// System prompts have no place in a session file and are rejected.
+func normalizeRole(role string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(role)) {
+	case "user", "human":
+		return "user", nil
+	case "assistant", "ai", "model", "bot":
+		return "assistant", nil
+	}
+	return "", fmt.Errorf("unsupported role %q: want user or assistant", role)
+}
+
+// Options describes the session an import produces
+type Options struct {
+	// SessionID names the session file; empty generates a random one
+	SessionID string
+	// Cwd is the project directory recorded on every entry
+	Cwd string
+	// Start stamps messages without a timestamp, one second apart;
+	// zero uses the current time
+	Start time.Time
+}
+
+// WriteSession writes messages as session JSONL: one entry per message,
+// each linked to the one before it as Claude Code does
+func WriteSession(w io.Writer, messages []Message, opts Options) error {
+	start := opts.Start
+	if start.IsZero() {
+		start = time.Now()
+	}
+
+	out := bufio.NewWriter(w)
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(false)
+	parent := ""
+	for i, message := range messages {
+		timestamp := message.Timestamp
+		if timestamp.IsZero() {
+			timestamp = start.Add(time.Duration(i) * time.Second)
+		}
+
+		// Claude Code stores assistant replies as content blocks and user
+		// prompts as plain strings
+		var content interface{} = message.Content
+		if message.Role == "assistant" {
+			content = []map[string]string{{"type": "text", "text": message.Content}}
+		}
+
+		id, err := newUUID()
+		if err != nil {
+			return err
+		}
+		entry := map[string]interface{}{
+			"type":       message.Role,
+			"uuid":       id,
+			"parentUuid": nil,
+			"sessionId":  opts.SessionID,
+			"timestamp":  timestamp.UTC().Format(time.RFC3339Nano),
+			"cwd":        opts.Cwd,
+			"message":    map[string]interface{}{"role": message.Role, "content": content},
+		}
+		if parent != "" {
+			entry["parentUuid"] = parent
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+		parent = id
+	}
+	return out.Flush()
+}
+
+// Import converts the conversation in src, read with format, into a new
+// session file in projectDir and returns its path. The path is also
+// returned when only dating the written file fails.
+func Import(src io.Reader, format Format, projectDir string, opts Options) (string, error) {
+	messages, err := format.Parse(src)
+	if err != nil {
+		return "", err
+	}
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no messages to import")
+	}
+
+	if opts.SessionID == "" {
+		if opts.SessionID, err = newUUID(); err != nil {
+			return "", err
+		}
+	} else if !validSessionID(opts.SessionID) {
+		return "", fmt.Errorf("invalid session ID %q: it names the session file, so it cannot hold a path", opts.SessionID)
+	}
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Never overwrite an existing session
+	path := filepath.Join(projectDir, opts.SessionID+".jsonl")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	if err := WriteSession(file, messages, opts); err != nil {
+		file.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	// The browser dates sessions by file time, so match the conversation
+	if last := messages[len(messages)-1].Timestamp; !last.IsZero() {
+		if err := os.Chtimes(path, last, last); err != nil {
+			return path, fmt.Errorf("setting the session's time: %w", err)
+		}
+	}
+	return path, nil
+}
+
+// validSessionID reports whether id is a single file name, so the session
+// cannot be written outside the project directory
+func validSessionID(id string) bool {
+	return id != "." && id != ".." && !strings.ContainsAny(id, `/\`) && filepath.Base(id) == id
+}
+
+// newUUID returns a random (version 4) UUID, the form Claude Code uses for
+// session and message IDs
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generating an ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
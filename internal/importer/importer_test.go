@@ -0,0 +1,86 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+)
+
+// An imported conversation reads back through the parser like a native one
+func TestImportJSONReadsBack(t *testing.T) {
+	src := strings.NewReader(`[
+		{"role": "user", "content": "How do I reverse a list?"},
+		{"role": "assistant", "content": "Use slices.Reverse."},
+		{"role": "human", "content": "Thanks"}
+	]`)
+	format, err := Lookup("json")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	path, err := Import(src, format, dir, Options{SessionID: "imported", Cwd: "/work/app", Start: start})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+
+	sessions, err := parser.NewParser().ListSessions(dir)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "imported" {
+		t.Fatalf("Expected the imported session, got %+v", sessions)
+	}
+	if sessions[0].MessageCount != 3 || sessions[0].Title != "How do I reverse a list?" {
+		t.Errorf("Unexpected metadata: %+v", sessions[0])
+	}
+
+	messages, err := parser.NewParser().ParseMessages(path)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(messages) != 3 || messages[1].Content != "Use slices.Reverse." || messages[2].Role != "user" {
+		t.Errorf("Unexpected messages: %+v", messages)
+	}
+}
+
+func TestImportRejectsUnknownRole(t *testing.T) {
+	src := strings.NewReader(`[{"role": "system", "content": "Be terse"}]`)
+	if _, err := Import(src, jsonFormat{}, t.TempDir(), Options{}); err == nil {
+		t.Error("Expected an error for a system message")
+	}
+}
+
+func TestImportKeepsExistingSession(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{SessionID: "taken"}
+	if _, err := Import(strings.NewReader(`[{"role":"user","content":"first"}]`), jsonFormat{}, dir, opts); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if _, err := Import(strings.NewReader(`[{"role":"user","content":"second"}]`), jsonFormat{}, dir, opts); err == nil {
+		t.Error("Expected importing over an existing session to fail")
+	}
+	data, err := os.ReadFile(dir + "/taken.jsonl")
+	if err != nil || !strings.Contains(string(data), "first") {
+		t.Errorf("Expected the first import to survive, got %q (%v)", data, err)
+	}
+}
+
+func TestImportRejectsPathIDs(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "project")
+	for _, id := range []string{"../escape", "..", "a/b", `a\b`, "."} {
+		src := strings.NewReader(`[{"role":"user","content":"hi"}]`)
+		if _, err := Import(src, jsonFormat{}, project, Options{SessionID: id}); err == nil {
+			t.Errorf("Expected session ID %q to be rejected", id)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.jsonl")); err == nil {
+		t.Error("Expected nothing written outside the project directory")
+	}
+}
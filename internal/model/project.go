@@ -0,0 +1,53 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProjectInfo describes a project directory under the Claude projects root
+type ProjectInfo struct {
+	Name         string // encoded directory name, e.g. "-Users-me-Projects-app"
+	Path         string // full path of the project directory
+	SessionCount int
+	LastActive   time.Time
+}
+
+// DecodedPath returns the filesystem path the project name was encoded from
+func (p ProjectInfo) DecodedPath() string {
+	return DecodeProjectPath(p.Name)
+}
+
+// DecodeProjectPath reverses Claude's path encoding, turning
+// "-Users-me-Projects-app" back into "/Users/me/Projects/app".
+// Dashes that were part of real directory names cannot be told apart from
+// separators, so the result is best-effort.
+func DecodeProjectPath(name string) string {
+	return strings.ReplaceAll(name, "-", string(filepath.Separator))
+}
+
+// IsUnderHome reports whether path lies inside the user's home directory
+func IsUnderHome(path string) bool {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return false
+	}
+	return path == home || strings.HasPrefix(path, home+string(filepath.Separator))
+}
+
+// ShortenHome replaces the user's home directory prefix with "~"
+func ShortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if path == home {
+		return "~"
+	}
+	if strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + path[len(home):]
+	}
+	return path
+}
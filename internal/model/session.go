@@ -11,6 +11,38 @@ type SessionInfo struct {
 	ID         string
 	FilePath   string
 	LastActive time.Time
+	Project    string // encoded project directory name
+	Title      string // first user prompt, single line
+	Preview    string // start of the last message, single line
+	SizeBytes  int64  // size of the JSONL file
+	Cwd        string // directory the session was run in, when recorded
+	LinkedID   string // session ID recorded inside the file; a continuation names the one it continues
+
+	// Parts are the files of a session merged from continuations, oldest
+	// first. It is empty for a session kept in a single file.
+	Parts []string
+
+	// Filled by the listing scan; a full parse is authoritative
+	MessageCount int
+	CostUSD      float64
+}
+
+// ActiveWindow is how recently a session file must have been written to
+// count as still in progress
+const ActiveWindow = time.Minute
+
+// IsActive reports whether Claude Code is likely still appending to the session
+func (s SessionInfo) IsActive() bool {
+	return time.Since(s.LastActive) < ActiveWindow
+}
+
+// ShortID returns the first group of the session UUID followed by an
+// ellipsis, e.g. "a1b2c3d4…". Use ID for anything that acts on the session.
+func (s SessionInfo) ShortID() string {
+	if i := strings.IndexByte(s.ID, '-'); i > 0 && i < len(s.ID)-1 {
+		return s.ID[:i] + "…"
+	}
+	return s.ID
 }
 
 // GetSessionID extracts the session ID from a filename
@@ -26,11 +58,64 @@ type FullSession struct {
 	Summary         string
 	LastActive      time.Time
 	MessageCount    int
+	ImageCount      int // images attached to messages, including tool results
 	TotalCostUSD    float64
-	LastRawMessages []string
+	Model           string // model of the most recent message that named one
+	Tokens          TokenUsage
+	LastRawMessages []string       // last JSON lines of the file, newest first
+	ReferencedFiles []string       // distinct paths from tool calls, in first-use order
+	FileCount       int            // distinct paths, including those past the ReferencedFiles cap
+	SlashCommands   []SlashCommand // commands the user ran, in first-use order
+}
+
+// SlashCommand counts the uses of one slash command, such as /compact
+type SlashCommand struct {
+	Name  string
+	Count int
+}
+
+// TokenUsage totals the token counts reported by assistant messages
+type TokenUsage struct {
+	Input         int // uncached input tokens
+	Output        int
+	CacheCreation int // input tokens written to the prompt cache
+	CacheRead     int // input tokens served from the prompt cache
+}
+
+// Total returns every input and output token counted
+func (u TokenUsage) Total() int {
+	return u.Input + u.Output + u.CacheCreation + u.CacheRead
+}
+
+// CacheHitRatio returns the share of input tokens served from the prompt
+// cache, from 0 to 1, and false when there was no input to share out
+func (u TokenUsage) CacheHitRatio() (float64, bool) {
+	input := u.Input + u.CacheCreation + u.CacheRead
+	if input == 0 {
+		return 0, false
+	}
+	return float64(u.CacheRead) / float64(input), true
 }
 
 // GetResumeCommand returns the command to resume this session
 func (s *FullSession) GetResumeCommand() string {
 	return "claude --resume " + s.ID
-}
\ No newline at end of file
+}
+
+// Message represents a single conversation turn in a session
+type Message struct {
+	LineNumber int // 1-based line number in the JSONL file
+	Role       string
+	Content    string
+	Timestamp  time.Time
+	Tool       bool // holds only tool calls or tool results, no prose
+}
+
+// RoleTitle returns the role capitalized for a heading, e.g. "User", or
+// "Unknown" for a malformed line that named none
+func (m Message) RoleTitle() string {
+	if m.Role == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(m.Role[:1]) + m.Role[1:]
+}
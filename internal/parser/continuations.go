@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"sort"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// mergeContinuations folds sessions that continue one another into one
+// entry each. A file continues another when the session ID recorded in it
+// is the other file's ID, following chains back to the first file. The
+// merged entry is listed under its newest file, which is what resuming
+// picks up, and the parser remembers its parts for parsing by that path.
+func (p *Parser) mergeContinuations(sessions []model.SessionInfo) []model.SessionInfo {
+	byID := make(map[string]int, len(sessions))
+	for i, session := range sessions {
+		byID[session.ID] = i
+	}
+
+	// root follows the links back to the first file of a conversation,
+	// stopping at links to files not listed and at cycles
+	root := func(i int) string {
+		id := sessions[i].ID
+		seen := map[string]bool{id: true}
+		for {
+			link := sessions[byID[id]].LinkedID
+			if _, ok := byID[link]; !ok || seen[link] {
+				return id
+			}
+			seen[link] = true
+			id = link
+		}
+	}
+
+	var order []string
+	groups := make(map[string][]model.SessionInfo)
+	for i, session := range sessions {
+		r := root(i)
+		if _, ok := groups[r]; !ok {
+			order = append(order, r)
+		}
+		groups[r] = append(groups[r], session)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	merged := make([]model.SessionInfo, 0, len(order))
+	for _, r := range order {
+		group := groups[r]
+		if len(group) == 1 {
+			delete(p.continuations, group[0].FilePath)
+			merged = append(merged, group[0])
+			continue
+		}
+		session := mergeInfos(group)
+		p.continuations[session.FilePath] = session.Parts
+		merged = append(merged, session)
+	}
+	return merged
+}
+
+// mergeInfos combines the list entries of a conversation's files into one,
+// under the newest file's ID and path, with the title of the first file
+// that has one and the totals of them all
+func mergeInfos(parts []model.SessionInfo) model.SessionInfo {
+	sort.SliceStable(parts, func(i, j int) bool {
+		if parts[i].LastActive.Equal(parts[j].LastActive) {
+			return parts[i].ID < parts[j].ID
+		}
+		return parts[i].LastActive.Before(parts[j].LastActive)
+	})
+
+	merged := parts[len(parts)-1]
+	merged.Title, merged.Cwd = "", ""
+	merged.MessageCount, merged.CostUSD, merged.SizeBytes = 0, 0, 0
+	merged.Parts = nil
+	for _, part := range parts {
+		if merged.Title == "" {
+			merged.Title = part.Title
+		}
+		if merged.Cwd == "" {
+			merged.Cwd = part.Cwd
+		}
+		if part.Preview != "" {
+			merged.Preview = part.Preview
+		}
+		merged.MessageCount += part.MessageCount
+		merged.CostUSD += part.CostUSD
+		merged.SizeBytes += part.SizeBytes
+		merged.Parts = append(merged.Parts, part.FilePath)
+	}
+	return merged
+}
+
+// parts returns the files of the merged session listed under filePath, or
+// nil when it is a single file
+func (p *Parser) parts(filePath string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.continuations[filePath]
+}
+
+// scanMerged reads the list entry of a merged session afresh from all of
+// its parts
+func (p *Parser) scanMerged(parts []string) (model.SessionInfo, error) {
+	infos := make([]model.SessionInfo, 0, len(parts))
+	for _, part := range parts {
+		info, err := p.scanFile(part)
+		if err != nil {
+			return model.SessionInfo{}, err
+		}
+		infos = append(infos, info)
+	}
+	merged := mergeInfos(infos)
+
+	p.mu.Lock()
+	p.continuations[merged.FilePath] = merged.Parts
+	p.mu.Unlock()
+	return merged, nil
+}
+
+// parseMerged parses every part of a merged session into one, counting and
+// totalling across them. Fields that describe the latest state, such as
+// the model, summary and last raw lines, come from the newest part that
+// has them.
+func (p *Parser) parseMerged(parts []string) (*model.FullSession, error) {
+	sessions := make([]*model.FullSession, 0, len(parts))
+	for _, part := range parts {
+		session, err := p.parseFile(part)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	newest := sessions[len(sessions)-1]
+	merged := &model.FullSession{ID: newest.ID, FilePath: newest.FilePath}
+	seenFiles := make(map[string]bool)
+	slashIndex := make(map[string]int)
+	for _, session := range sessions {
+		if session.LastActive.After(merged.LastActive) {
+			merged.LastActive = session.LastActive
+		}
+		if session.Summary != "" {
+			merged.Summary = session.Summary
+		}
+		if session.Model != "" {
+			merged.Model = session.Model
+		}
+		merged.MessageCount += session.MessageCount
+		merged.ImageCount += session.ImageCount
+		merged.TotalCostUSD += session.TotalCostUSD
+		merged.Tokens.Input += session.Tokens.Input
+		merged.Tokens.Output += session.Tokens.Output
+		merged.Tokens.CacheCreation += session.Tokens.CacheCreation
+		merged.Tokens.CacheRead += session.Tokens.CacheRead
+
+		for _, path := range session.ReferencedFiles {
+			if seenFiles[path] {
+				continue
+			}
+			seenFiles[path] = true
+			merged.FileCount++
+			if len(merged.ReferencedFiles) < maxReferencedFiles {
+				merged.ReferencedFiles = append(merged.ReferencedFiles, path)
+			}
+		}
+		// Paths past a part's cap cannot be checked against the others
+		merged.FileCount += session.FileCount - len(session.ReferencedFiles)
+		for _, command := range session.SlashCommands {
+			if i, ok := slashIndex[command.Name]; ok {
+				merged.SlashCommands[i].Count += command.Count
+			} else {
+				slashIndex[command.Name] = len(merged.SlashCommands)
+				merged.SlashCommands = append(merged.SlashCommands, command)
+			}
+		}
+	}
+
+	// Last raw lines run newest first, on into the parts before
+	for i := len(sessions) - 1; i >= 0; i-- {
+		for _, line := range sessions[i].LastRawMessages {
+			if len(merged.LastRawMessages) < p.rawMessages {
+				merged.LastRawMessages = append(merged.LastRawMessages, line)
+			}
+		}
+	}
+	return merged, nil
+}
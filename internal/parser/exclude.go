@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// IgnoreProjectsFile, in the projects root, lists projects to leave out of
+// everything gathered across projects, one per line like ExcludeProjects
+const IgnoreProjectsFile = ".session-browser-ignore-projects"
+
+// excludeFilter returns whether a project directory under rootDir is left
+// out, by the parser's patterns and those in rootDir's ignore file. It is
+// nil when nothing is excluded.
+func (p *Parser) excludeFilter(rootDir string) func(name string) bool {
+	patterns := append(append([]string(nil), p.exclude...), readIgnoreFile(filepath.Join(rootDir, IgnoreProjectsFile))...)
+	if len(patterns) == 0 {
+		return nil
+	}
+	return func(name string) bool {
+		return matchesProject(patterns, name)
+	}
+}
+
+// readIgnoreFile reads the patterns of an ignore file, skipping blank lines
+// and # comments. A missing or unreadable file excludes nothing.
+func readIgnoreFile(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var patterns []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns
+}
+
+// matchesProject reports whether the project directory name matches any
+// pattern: its encoded name, its decoded path or a directory above it
+// (with ~ for the home directory), or a glob of the decoded path
+func matchesProject(patterns []string, name string) bool {
+	path := model.DecodeProjectPath(name)
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		pattern = filepath.Clean(expandHome(pattern))
+		if path == pattern || strings.HasPrefix(path, pattern+string(filepath.Separator)) {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandHome turns a leading ~ into the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return home + path[1:]
+}
+
+// ExcludedProjects counts the project directories under rootDir that the
+// exclude patterns leave out
+func (p *Parser) ExcludedProjects(rootDir string) int {
+	excluded := p.excludeFilter(rootDir)
+	if excluded == nil {
+		return 0
+	}
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.IsDir() && excluded(entry.Name()) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,53 @@
+package parser
+
+// Fields names the JSON keys the parser reads from each JSONL entry. Forks
+// of Claude Code may use different names for the same data.
+type Fields struct {
+	Type      string // entry kind: "user", "assistant", "summary", ...
+	Timestamp string // RFC 3339 time the entry was written
+	Cost      string // cost of the entry in USD
+	Message   string // object holding the message body
+	Content   string // message body, inside Message
+	Model     string // model that produced the message, inside Message
+	Usage     string // token counts of the message, inside Message
+	Cwd       string // directory the session was run in
+	Title     string // title given to the session, e.g. by renaming it
+	SessionID string // session the entry belongs to, shared by continuations
+}
+
+// DefaultFields returns the keys written by stock Claude Code
+func DefaultFields() Fields {
+	return Fields{
+		Type:      "type",
+		Timestamp: "timestamp",
+		Cost:      "costUSD",
+		Message:   "message",
+		Content:   "content",
+		Model:     "model",
+		Usage:     "usage",
+		Cwd:       "cwd",
+		Title:     "customTitle",
+		SessionID: "sessionId",
+	}
+}
+
+// withDefaults fills any empty key with the stock name
+func (f Fields) withDefaults() Fields {
+	defaults := DefaultFields()
+	fill := func(value *string, fallback string) {
+		if *value == "" {
+			*value = fallback
+		}
+	}
+	fill(&f.Type, defaults.Type)
+	fill(&f.Timestamp, defaults.Timestamp)
+	fill(&f.Cost, defaults.Cost)
+	fill(&f.Message, defaults.Message)
+	fill(&f.Content, defaults.Content)
+	fill(&f.Model, defaults.Model)
+	fill(&f.Usage, defaults.Usage)
+	fill(&f.Cwd, defaults.Cwd)
+	fill(&f.Title, defaults.Title)
+	fill(&f.SessionID, defaults.SessionID)
+	return f
+}
@@ -0,0 +1,41 @@
+package parser
+
+import "regexp"
+
+// imagePlaceholder stands in for an image attached to a message, in the
+// transcript and in raw lines shown on screen
+const imagePlaceholder = "[image]"
+
+// imageDataPattern finds the base64 payload of an image block's source,
+// long enough that it cannot be ordinary text. Slashes may be escaped.
+var imageDataPattern = regexp.MustCompile(`("data"\s*:\s*")(?:[A-Za-z0-9+=]|\\?/){100,}"`)
+
+// countImages counts the image blocks in message content, including those
+// inside tool results such as screenshots
+func countImages(content interface{}) int {
+	blocks, ok := content.([]interface{})
+	if !ok {
+		return 0
+	}
+	count := 0
+	for _, item := range blocks {
+		block, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch block["type"] {
+		case "image":
+			count++
+		case "tool_result":
+			count += countImages(block["content"])
+		}
+	}
+	return count
+}
+
+// ElideImages replaces the base64 data of images in a raw JSON line with a
+// placeholder, so a screenshot does not fill the screen with noise. The
+// line stays valid JSON with its keys in their original order.
+func ElideImages(line string) string {
+	return imageDataPattern.ReplaceAllString(line, `${1}`+imagePlaceholder+`"`)
+}
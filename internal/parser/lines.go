@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+// replacementChar stands in for invalid UTF-8, as encoding/json does
+var replacementChar = []byte(string(utf8.RuneError))
+
+// lineReader reads newline-delimited lines of any length with a
+// bufio.Scanner-like API. bufio.Scanner stops at its max token size, and
+// sessions with huge tool outputs or inline images exceed any sane limit,
+// which used to cut parsing short without an error.
+type lineReader struct {
+	reader *bufio.Reader
+	line   []byte
+	err    error
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{reader: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Scan advances to the next line, returning false at EOF or on error
+func (l *lineReader) Scan() bool {
+	if l.err != nil {
+		return false
+	}
+
+	line, err := l.reader.ReadBytes('\n')
+	if err != nil {
+		l.err = err
+		// The final line may lack a trailing newline
+		if err != io.EOF || len(line) == 0 {
+			return false
+		}
+	}
+
+	l.line = bytes.TrimRight(line, "\r\n")
+	// Tool output can carry stray bytes; fix them here so every rune count
+	// and cut further on sees whole characters
+	if !utf8.Valid(l.line) {
+		l.line = bytes.ToValidUTF8(l.line, replacementChar)
+	}
+	return true
+}
+
+// Bytes returns the current line without its line ending, with invalid
+// UTF-8 replaced by U+FFFD
+func (l *lineReader) Bytes() []byte {
+	return l.line
+}
+
+// Text returns the current line as a string
+func (l *lineReader) Text() string {
+	return string(l.line)
+}
+
+// Err returns the first non-EOF error encountered
+func (l *lineReader) Err() error {
+	if l.err == io.EOF {
+		return nil
+	}
+	return l.err
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// previewLength caps the stored preview; the list truncates further to fit
+const previewLength = 80
+
+// scanMetadata reads a session file for the cheap, list-level details:
+// a title, a preview of the last message, the message count, the working
+// directory and the total cost. Entries are only decoded one level
+// deep; message bodies stay raw until one is chosen for display.
+// It fails only when the file cannot be opened.
+//
+// The title is, in order of precedence: the last explicit title written to
+// the file (f.Title, set when the session is renamed), else the first
+// titleMessages user prompts joined by spaces, for sessions that open with a
+// greeting or split their request across messages.
+func scanMetadata(session *model.SessionInfo, f Fields, titleMessages int) error {
+	file, err := os.Open(session.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := newLineReader(file)
+
+	var lastContent json.RawMessage
+	var explicitTitle string
+	var prompts []string
+	for scanner.Scan() {
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+
+		var cost float64
+		if json.Unmarshal(entry[f.Cost], &cost) == nil {
+			session.CostUSD += cost
+		}
+		if session.Cwd == "" {
+			json.Unmarshal(entry[f.Cwd], &session.Cwd)
+		}
+		if session.LinkedID == "" {
+			json.Unmarshal(entry[f.SessionID], &session.LinkedID)
+		}
+		var title string
+		if json.Unmarshal(entry[f.Title], &title) == nil && strings.TrimSpace(title) != "" {
+			explicitTitle = singleLine(title, previewLength)
+		}
+
+		var entryType string
+		json.Unmarshal(entry[f.Type], &entryType)
+		if entryType != "user" && entryType != "assistant" {
+			continue
+		}
+		session.MessageCount++
+
+		var message map[string]json.RawMessage
+		if json.Unmarshal(entry[f.Message], &message) != nil {
+			continue
+		}
+		content := message[f.Content]
+		if len(content) == 0 {
+			continue
+		}
+
+		// Keep the raw content and only decode the one we end up showing
+		lastContent = append(lastContent[:0], content...)
+
+		if len(prompts) < titleMessages && entryType == "user" {
+			text := decodeContent(content)
+			if text != "" && !strings.Contains(text, "system-reminder") {
+				prompts = append(prompts, text)
+			}
+		}
+	}
+
+	session.Title = singleLine(strings.Join(prompts, " "), previewLength)
+	if explicitTitle != "" {
+		session.Title = explicitTitle
+	}
+
+	if lastContent != nil {
+		session.Preview = singleLine(decodeContent(lastContent), previewLength)
+	}
+	return nil
+}
+
+// decodeContent flattens a raw message content field into text
+func decodeContent(raw json.RawMessage) string {
+	var content interface{}
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return ""
+	}
+	return extractContent(content)
+}
+
+// singleLine collapses whitespace and caps text at maxRunes runes
+func singleLine(text string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) > maxRunes {
+		runes := []rune(text)
+		text = string(runes[:maxRunes])
+	}
+	return text
+}
@@ -1,54 +1,364 @@
 package parser
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/davidpaquet/claude-session-browser/internal/model"
 )
 
 // Parser handles parsing
-type Parser struct{}
+type Parser struct {
+	fields      Fields
+	summary     SummaryOptions
+	rawMessages int
+	merge       bool
+	ellipsis    string
+	exclude     []string
+	titleMsgs   int
 
-// NewParser creates a new parser
+	// continuations maps the file standing for a merged session to all of
+	// its parts, so parsing by that path covers the whole conversation
+	mu            sync.Mutex
+	continuations map[string][]string
+}
+
+// Options configures a Parser. Zero values fall back to the defaults; an
+// empty Separator is kept when other summary options are set.
+type Options struct {
+	Fields  Fields
+	Summary SummaryOptions
+	// RawMessages is how many of the last lines ParseFullSession keeps in
+	// LastRawMessages; 0 keeps one
+	RawMessages int
+	// MergeContinuations lists a conversation split across several files
+	// as one session; see mergeContinuations
+	MergeContinuations bool
+	// Ellipsis ends summary parts cut to MessageLength; empty keeps "..."
+	Ellipsis string
+	// ExcludeProjects leaves projects out of ListProjects and
+	// ListAllSessions; see matchesProject for the pattern forms
+	ExcludeProjects []string
+	// TitleMessages is how many of the first user messages make up a
+	// session's derived title; 0 uses the first alone
+	TitleMessages int
+}
+
+// SummaryOptions shapes the summary built from the last user messages when
+// a session has no summary line of its own
+type SummaryOptions struct {
+	MessageLength int    // characters kept from each message
+	Messages      int    // how many of the last user messages to join
+	Separator     string // placed between the messages
+	KeepNewlines  bool   // keep each message's line breaks and spacing as written
+}
+
+// DefaultSummaryOptions joins the last three user messages, 150 characters
+// each, with " | "
+func DefaultSummaryOptions() SummaryOptions {
+	return SummaryOptions{MessageLength: 150, Messages: 3, Separator: " | "}
+}
+
+// NewParser creates a parser for stock Claude Code session files
 func NewParser() *Parser {
-	return &Parser{}
+	return NewParserWithOptions(Options{})
+}
+
+// NewParserWithFields creates a parser that reads the given JSON keys.
+// Empty keys fall back to the stock names.
+func NewParserWithFields(fields Fields) *Parser {
+	return NewParserWithOptions(Options{Fields: fields})
+}
+
+// NewParserWithOptions creates a parser with the given field names and
+// summary shape
+func NewParserWithOptions(opts Options) *Parser {
+	defaults := DefaultSummaryOptions()
+	summary := opts.Summary
+	if summary == (SummaryOptions{}) {
+		summary = defaults
+	}
+	if summary.MessageLength <= 0 {
+		summary.MessageLength = defaults.MessageLength
+	}
+	if summary.Messages <= 0 {
+		summary.Messages = defaults.Messages
+	}
+	rawMessages := opts.RawMessages
+	if rawMessages <= 0 {
+		rawMessages = 1
+	}
+	ellipsis := opts.Ellipsis
+	if ellipsis == "" {
+		ellipsis = "..."
+	}
+	titleMessages := opts.TitleMessages
+	if titleMessages <= 0 {
+		titleMessages = 1
+	}
+	return &Parser{
+		fields:        opts.Fields.withDefaults(),
+		summary:       summary,
+		rawMessages:   rawMessages,
+		merge:         opts.MergeContinuations,
+		ellipsis:      ellipsis,
+		exclude:       opts.ExcludeProjects,
+		titleMsgs:     titleMessages,
+		continuations: make(map[string][]string),
+	}
 }
 
-// ListSessions returns basic session info without parsing content
+// metadataWorkers bounds how many session files are scanned at once
+const metadataWorkers = 4
+
+// ListSessions returns session info for the list, including the title,
+// preview and message count from a lightweight metadata scan of each file.
+// Files that cannot be read are left out and reported in a *SkippedError
+// returned with the rest of the sessions.
 func (p *Parser) ListSessions(claudeDir string) ([]model.SessionInfo, error) {
+	if IsSessionFile(claudeDir) {
+		// A single session file browsed on its own
+		session, err := p.scanFile(claudeDir)
+		if err != nil {
+			return nil, err
+		}
+		return []model.SessionInfo{session}, nil
+	}
+
+	entries, err := sessionEntries(claudeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each worker stats and fills in its own sessions, so the per-file
+	// syscalls run in parallel with the scans and only the errors need
+	// collecting afterwards
+	sessions := make([]model.SessionInfo, len(entries))
+	scanErrs := make([]error, len(entries))
+	jobs := make(chan int, len(entries))
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < metadataWorkers && w < len(entries); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				info, err := entries[i].Info()
+				if err != nil {
+					scanErrs[i] = err
+					continue
+				}
+				sessions[i] = newSessionInfo(claudeDir, entries[i], info)
+				scanErrs[i] = scanMetadata(&sessions[i], p.fields, p.titleMsgs)
+			}
+		}()
+	}
+	wg.Wait()
+
+	skipped := &SkippedError{}
+	readable := sessions[:0]
+	for i := range sessions {
+		if scanErrs[i] != nil {
+			skipped.skipSession(scanErrs[i])
+			continue
+		}
+		readable = append(readable, sessions[i])
+	}
+	if p.merge {
+		readable = p.mergeContinuations(readable)
+	}
+
+	return readable, skipped.orNil()
+}
+
+// IsSessionFile reports whether path is a session file rather than a
+// directory of them
+func IsSessionFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && strings.HasSuffix(path, ".jsonl")
+}
+
+// sessionEntries returns the session files of a project directory without
+// statting them
+func sessionEntries(claudeDir string) ([]os.DirEntry, error) {
 	entries, err := os.ReadDir(claudeDir)
 	if err != nil {
+		if info, statErr := os.Stat(claudeDir); statErr == nil && !info.IsDir() {
+			return nil, fmt.Errorf("%s is a file, not a project directory", claudeDir)
+		}
 		return nil, err
 	}
 
-	var sessions []model.SessionInfo
+	files := entries[:0]
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".jsonl") {
 			continue
 		}
+		files = append(files, entry)
+	}
+	return files, nil
+}
 
+// newSessionInfo is the list entry of a session file before its contents
+// are read
+func newSessionInfo(claudeDir string, entry os.DirEntry, info os.FileInfo) model.SessionInfo {
+	return model.SessionInfo{
+		ID:         model.GetSessionID(entry.Name()),
+		FilePath:   filepath.Join(claudeDir, entry.Name()),
+		LastActive: info.ModTime(), // Use file modification time
+		SizeBytes:  info.Size(),
+		Project:    filepath.Base(claudeDir),
+	}
+}
+
+// listSessionFiles returns basic session info without reading file contents,
+// counting files it could not stat
+func listSessionFiles(claudeDir string) ([]model.SessionInfo, *SkippedError, error) {
+	entries, err := sessionEntries(claudeDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	skipped := &SkippedError{}
+	sessions := make([]model.SessionInfo, 0, len(entries))
+	for _, entry := range entries {
 		info, err := entry.Info()
 		if err != nil {
+			skipped.skipSession(err)
+			continue
+		}
+		sessions = append(sessions, newSessionInfo(claudeDir, entry, info))
+	}
+
+	return sessions, skipped, nil
+}
+
+// ScanSession reads one session file afresh, the way ListSessions reads
+// every file of a project, for refreshing a single list entry
+func (p *Parser) ScanSession(filePath string) (model.SessionInfo, error) {
+	if parts := p.parts(filePath); len(parts) > 1 {
+		return p.scanMerged(parts)
+	}
+	return p.scanFile(filePath)
+}
+
+// scanFile reads the list entry of one session file
+func (p *Parser) scanFile(filePath string) (model.SessionInfo, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return model.SessionInfo{}, err
+	}
+
+	session := model.SessionInfo{
+		ID:         model.GetSessionID(filePath),
+		FilePath:   filePath,
+		LastActive: info.ModTime(),
+		SizeBytes:  info.Size(),
+		Project:    filepath.Base(filepath.Dir(filePath)),
+	}
+	if err := scanMetadata(&session, p.fields, p.titleMsgs); err != nil {
+		return model.SessionInfo{}, err
+	}
+	return session, nil
+}
+
+// ListAllSessions returns the sessions of every project under rootDir
+// that is not excluded, each tagged with its project directory name.
+// Unreadable projects and files are reported in a *SkippedError returned
+// with the rest.
+func (p *Parser) ListAllSessions(rootDir string) ([]model.SessionInfo, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := p.excludeFilter(rootDir)
+	skipped := &SkippedError{}
+	var sessions []model.SessionInfo
+	for _, entry := range entries {
+		if !entry.IsDir() || (excluded != nil && excluded(entry.Name())) {
+			continue
+		}
+
+		projectSessions, err := p.ListSessions(filepath.Join(rootDir, entry.Name()))
+		var partial *SkippedError
+		if errors.As(err, &partial) {
+			skipped.merge(partial)
+		} else if err != nil {
+			skipped.skipProject(err)
+			continue
+		}
+		sessions = append(sessions, projectSessions...)
+	}
+
+	return sessions, skipped.orNil()
+}
+
+// ListProjects returns every project directory under rootDir that holds at
+// least one session and is not excluded, most recently active first
+func (p *Parser) ListProjects(rootDir string) ([]model.ProjectInfo, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := p.excludeFilter(rootDir)
+	var projects []model.ProjectInfo
+	for _, entry := range entries {
+		if !entry.IsDir() || (excluded != nil && excluded(entry.Name())) {
+			continue
+		}
+
+		projectDir := filepath.Join(rootDir, entry.Name())
+		sessions, _, err := listSessionFiles(projectDir)
+		if err != nil || len(sessions) == 0 {
 			continue
 		}
 
-		sessions = append(sessions, model.SessionInfo{
-			ID:         model.GetSessionID(entry.Name()),
-			FilePath:   filepath.Join(claudeDir, entry.Name()),
-			LastActive: info.ModTime(), // Use file modification time
-		})
+		project := model.ProjectInfo{
+			Name:         entry.Name(),
+			Path:         projectDir,
+			SessionCount: len(sessions),
+		}
+		for _, session := range sessions {
+			if session.LastActive.After(project.LastActive) {
+				project.LastActive = session.LastActive
+			}
+		}
+		projects = append(projects, project)
 	}
 
-	return sessions, nil
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].LastActive.After(projects[j].LastActive)
+	})
+
+	return projects, nil
 }
 
-// ParseFullSession parses a single session with all details
+// ParseFullSession parses a single session with all details. A merged
+// session, known by the file ListSessions listed it under, covers every
+// one of its parts.
 func (p *Parser) ParseFullSession(filePath string) (*model.FullSession, error) {
+	if parts := p.parts(filePath); len(parts) > 1 {
+		return p.parseMerged(parts)
+	}
+	return p.parseFile(filePath)
+}
+
+// parseFile parses one session file with all details
+func (p *Parser) parseFile(filePath string) (*model.FullSession, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
@@ -60,13 +370,16 @@ func (p *Parser) ParseFullSession(filePath string) (*model.FullSession, error) {
 		FilePath: filePath,
 	}
 
-	scanner := bufio.NewScanner(file)
-	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	scanner := newLineReader(file)
 
 	var allLines []string
 	var lastUserMessages []string
 	messageCount := 0
 	totalCost := 0.0
+	seenFiles := make(map[string]bool)
+	seenUsage := make(map[string]bool)
+	slashIndex := make(map[string]int)
+	f := p.fields
 
 	// Read all lines
 	for scanner.Scan() {
@@ -81,7 +394,7 @@ func (p *Parser) ParseFullSession(filePath string) (*model.FullSession, error) {
 		var data map[string]interface{}
 		if err := json.Unmarshal([]byte(line), &data); err == nil {
 			// Count messages
-			if msgType, ok := data["type"].(string); ok {
+			if msgType, ok := data[f.Type].(string); ok {
 				if msgType == "user" || msgType == "assistant" {
 					messageCount++
 				}
@@ -93,10 +406,19 @@ func (p *Parser) ParseFullSession(filePath string) (*model.FullSession, error) {
 					}
 				}
 
-				// Collect user messages for fallback summary
+				// Collect user messages for fallback summary, counting
+				// the slash commands among them
 				if msgType == "user" {
-					if msg, ok := data["message"].(map[string]interface{}); ok {
-						if content, ok := msg["content"].(string); ok {
+					if msg, ok := data[f.Message].(map[string]interface{}); ok {
+						if name, ok := slashCommand(msg[f.Content]); ok {
+							if i, seen := slashIndex[name]; seen {
+								session.SlashCommands[i].Count++
+							} else {
+								slashIndex[name] = len(session.SlashCommands)
+								session.SlashCommands = append(session.SlashCommands, model.SlashCommand{Name: name, Count: 1})
+							}
+						}
+						if content, ok := msg[f.Content].(string); ok {
 							content = strings.TrimSpace(content)
 							if !strings.Contains(content, "system-reminder") {
 								lastUserMessages = append(lastUserMessages, content)
@@ -107,43 +429,274 @@ func (p *Parser) ParseFullSession(filePath string) (*model.FullSession, error) {
 			}
 
 			// Get timestamp
-			if ts, ok := data["timestamp"].(string); ok {
+			if ts, ok := data[f.Timestamp].(string); ok {
 				if t, err := time.Parse(time.RFC3339, ts); err == nil {
 					session.LastActive = t
 				}
 			}
 
 			// Get cost
-			if cost, ok := data["costUSD"].(float64); ok {
+			if cost, ok := data[f.Cost].(float64); ok {
 				totalCost += cost
 			}
+
+			// Remember the most recent model, total the tokens and count
+			// attached images
+			if msg, ok := data[f.Message].(map[string]interface{}); ok {
+				if name, ok := msg[f.Model].(string); ok && name != "" {
+					session.Model = name
+				}
+				addUsage(&session.Tokens, msg, f, seenUsage)
+				session.ImageCount += countImages(msg[f.Content])
+			}
+
+			// Collect files touched by tool calls
+			for _, path := range toolFilePaths(data, f) {
+				if seenFiles[path] {
+					continue
+				}
+				seenFiles[path] = true
+				session.FileCount++
+				if len(session.ReferencedFiles) < maxReferencedFiles {
+					session.ReferencedFiles = append(session.ReferencedFiles, path)
+				}
+			}
 		}
 	}
 
-	// Fallback: Set summary from last 3 user messages if no summary line was found
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Fallback: Set summary from the last user messages if no summary line was found
 	if session.Summary == "" && len(lastUserMessages) > 0 {
-		start := len(lastUserMessages) - 3
+		start := len(lastUserMessages) - p.summary.Messages
 		if start < 0 {
 			start = 0
 		}
 		summaryParts := []string{}
 		for i := start; i < len(lastUserMessages); i++ {
-			msg := lastUserMessages[i]
-			if len(msg) > 150 {
-				msg = msg[:147] + "..."
+			part := lastUserMessages[i]
+			if !p.summary.KeepNewlines {
+				// Pasted code and paragraphs read as one line
+				part = strings.Join(strings.Fields(part), " ")
 			}
-			summaryParts = append(summaryParts, msg)
+			summaryParts = append(summaryParts, truncateSummaryPart(part, p.summary.MessageLength, p.ellipsis))
 		}
-		session.Summary = strings.Join(summaryParts, " | ")
+		session.Summary = strings.Join(summaryParts, p.summary.Separator)
 	}
 
-	// Get just the LAST raw message - complete and untruncated
-	if len(allLines) > 0 {
-		session.LastRawMessages = []string{allLines[len(allLines)-1]}
+	// Keep the last raw lines, newest first, complete and untruncated
+	for i := len(allLines) - 1; i >= 0 && len(session.LastRawMessages) < p.rawMessages; i-- {
+		session.LastRawMessages = append(session.LastRawMessages, allLines[i])
 	}
 
 	session.MessageCount = messageCount
 	session.TotalCostUSD = totalCost
 
 	return session, nil
-}
\ No newline at end of file
+}
+
+// truncateSummaryPart cuts msg to maxRunes characters, ending with ellipsis
+func truncateSummaryPart(msg string, maxRunes int, ellipsis string) string {
+	if utf8.RuneCountInString(msg) <= maxRunes {
+		return msg
+	}
+	runes := []rune(msg)
+	mark := utf8.RuneCountInString(ellipsis)
+	if maxRunes <= mark {
+		return string(runes[:maxRunes])
+	}
+	return string(runes[:maxRunes-mark]) + ellipsis
+}
+
+// addUsage adds a message's token counts to total. Claude Code writes one
+// line per content block, each repeating the usage of the whole message, so
+// a message ID is only counted once.
+func addUsage(total *model.TokenUsage, msg map[string]interface{}, f Fields, seen map[string]bool) {
+	usage, ok := msg[f.Usage].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if id, ok := msg["id"].(string); ok && id != "" {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+	}
+
+	count := func(key string) int {
+		n, _ := usage[key].(float64)
+		return int(n)
+	}
+	total.Input += count("input_tokens")
+	total.Output += count("output_tokens")
+	total.CacheCreation += count("cache_creation_input_tokens")
+	total.CacheRead += count("cache_read_input_tokens")
+}
+
+// maxReferencedFiles caps how many distinct file paths a session keeps;
+// FileCount still counts the rest
+const maxReferencedFiles = 200
+
+// fileInputKeys are the tool_use input fields that hold a file path
+var fileInputKeys = []string{"file_path", "notebook_path", "path"}
+
+// toolFilePaths returns the file paths passed to tool calls in an entry
+func toolFilePaths(data map[string]interface{}, f Fields) []string {
+	msg, ok := data[f.Message].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	blocks, ok := msg[f.Content].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, item := range blocks {
+		block, ok := item.(map[string]interface{})
+		if !ok || block["type"] != "tool_use" {
+			continue
+		}
+		input, ok := block["input"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range fileInputKeys {
+			if path, ok := input[key].(string); ok && path != "" {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// ParseMessages returns the user and assistant turns of a session in order,
+// across every part of a merged session
+func (p *Parser) ParseMessages(filePath string) ([]model.Message, error) {
+	parts := p.parts(filePath)
+	if len(parts) <= 1 {
+		messages, _, err := p.parseMessagesFile(filePath)
+		return messages, err
+	}
+
+	// Number lines on from the end of the previous part, as search does
+	var all []model.Message
+	offset := 0
+	for _, part := range parts {
+		messages, lines, err := p.parseMessagesFile(part)
+		if err != nil {
+			return nil, err
+		}
+		for i := range messages {
+			messages[i].LineNumber += offset
+		}
+		all = append(all, messages...)
+		offset += lines
+	}
+	return all, nil
+}
+
+// parseMessagesFile returns the turns of one session file and how many
+// lines it has
+func (p *Parser) parseMessagesFile(filePath string) ([]model.Message, int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	scanner := newLineReader(file)
+
+	f := p.fields
+	var messages []model.Message
+	lineNumber := 0
+	for scanner.Scan() {
+		// Count every line so numbers line up with ripgrep's line_number
+		lineNumber++
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &data); err != nil {
+			continue
+		}
+
+		msgType, _ := data[f.Type].(string)
+		if msgType != "user" && msgType != "assistant" {
+			continue
+		}
+
+		msg := model.Message{
+			LineNumber: lineNumber,
+			Role:       msgType,
+		}
+		if m, ok := data[f.Message].(map[string]interface{}); ok {
+			msg.Content = extractContent(m[f.Content])
+			msg.Tool = isToolOnly(m[f.Content])
+		}
+		if ts, ok := data[f.Timestamp].(string); ok {
+			if t, err := time.Parse(time.RFC3339, ts); err == nil {
+				msg.Timestamp = t
+			}
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, lineNumber, scanner.Err()
+}
+
+// isToolOnly reports whether content is made of tool_use and tool_result
+// blocks alone, the bulk of agentic sessions that readers usually skip
+func isToolOnly(content interface{}) bool {
+	blocks, ok := content.([]interface{})
+	if !ok || len(blocks) == 0 {
+		return false
+	}
+	for _, item := range blocks {
+		block, ok := item.(map[string]interface{})
+		if !ok || (block["type"] != "tool_use" && block["type"] != "tool_result") {
+			return false
+		}
+	}
+	return true
+}
+
+// extractContent flattens a message content field into displayable text.
+// Content is either a plain string or an array of typed blocks.
+func extractContent(content interface{}) string {
+	switch c := content.(type) {
+	case string:
+		return strings.TrimSpace(c)
+	case []interface{}:
+		var parts []string
+		for _, item := range c {
+			block, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			switch block["type"] {
+			case "text":
+				if text, ok := block["text"].(string); ok {
+					parts = append(parts, strings.TrimSpace(text))
+				}
+			case "tool_use":
+				name, _ := block["name"].(string)
+				parts = append(parts, "[tool: "+name+"]")
+			case "image":
+				parts = append(parts, imagePlaceholder)
+			case "tool_result":
+				if text := extractContent(block["content"]); text != "" {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
@@ -0,0 +1,654 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+func writeSession(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test-session.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	return path
+}
+
+// A session file given in place of a project directory lists just that
+// session; any other file is an error that says so
+func TestListSessionsSingleFile(t *testing.T) {
+	path := writeSession(t, `{"type":"user","message":{"role":"user","content":"one file"}}`)
+	sessions, err := NewParser().ListSessions(path)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].FilePath != path || sessions[0].Title != "one file" {
+		t.Errorf("Expected the one session, got %+v", sessions)
+	}
+
+	notes := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(notes, []byte("hi"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewParser().ListSessions(notes); err == nil || !strings.Contains(err.Error(), "is a file, not a project directory") {
+		t.Errorf("Expected a clear error for a plain file, got %v", err)
+	}
+}
+
+// Lines over bufio.Scanner's old 1MB limit used to stop parsing early
+func TestParseFullSessionLongLine(t *testing.T) {
+	huge := strings.Repeat("x", 2*1024*1024)
+	last := `{"type":"user","message":{"role":"user","content":"last message"}}`
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"first message"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"text","text":"`+huge+`"}]}}`,
+		last,
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+
+	if session.MessageCount != 3 {
+		t.Errorf("Expected 3 messages, got %d", session.MessageCount)
+	}
+	if len(session.LastRawMessages) != 1 || session.LastRawMessages[0] != last {
+		t.Errorf("Expected last raw message to be the final line")
+	}
+
+	messages, err := NewParser().ParseMessages(path)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Fatalf("Expected 3 parsed messages, got %d", len(messages))
+	}
+	if len(messages[1].Content) != len(huge) {
+		t.Errorf("Expected the long message to be kept whole, got %d bytes", len(messages[1].Content))
+	}
+	if messages[2].LineNumber != 3 {
+		t.Errorf("Expected last message on line 3, got %d", messages[2].LineNumber)
+	}
+}
+
+// Forks that rename fields parse the same once the names are configured
+func TestParseFullSessionCustomFields(t *testing.T) {
+	path := writeSession(t,
+		`{"kind":"user","ts":"2024-05-01T10:00:00Z","cost":0.5,"msg":{"body":"hello"}}`,
+		`{"kind":"assistant","ts":"2024-05-01T10:01:00Z","cost":0.25,"msg":{"body":"hi","engine":"fork-model"}}`,
+	)
+
+	p := NewParserWithFields(Fields{
+		Type:      "kind",
+		Timestamp: "ts",
+		Cost:      "cost",
+		Message:   "msg",
+		Content:   "body",
+		Model:     "engine",
+	})
+	session, err := p.ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+
+	if session.MessageCount != 2 {
+		t.Errorf("Expected 2 messages, got %d", session.MessageCount)
+	}
+	if session.TotalCostUSD != 0.75 {
+		t.Errorf("Expected cost 0.75, got %v", session.TotalCostUSD)
+	}
+	if session.Model != "fork-model" {
+		t.Errorf("Expected model fork-model, got %q", session.Model)
+	}
+	if want := "2024-05-01T10:01:00Z"; session.LastActive.UTC().Format(time.RFC3339) != want {
+		t.Errorf("Expected last active %s, got %v", want, session.LastActive)
+	}
+	if session.Summary != "hello" {
+		t.Errorf("Expected summary from the user message, got %q", session.Summary)
+	}
+}
+
+func TestParseFullSessionSummaryOptions(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"first question"}}`,
+		`{"type":"user","message":{"role":"user","content":"second question"}}`,
+		`{"type":"user","message":{"role":"user","content":"third question is rather long"}}`,
+	)
+
+	p := NewParserWithOptions(Options{
+		Summary: SummaryOptions{MessageLength: 12, Messages: 2, Separator: " / "},
+	})
+	session, err := p.ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+
+	want := "second qu... / third que..."
+	if session.Summary != want {
+		t.Errorf("Expected summary %q, got %q", want, session.Summary)
+	}
+
+	// Defaults keep the last three messages joined by " | "
+	session, err = NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	want = "first question | second question | third question is rather long"
+	if session.Summary != want {
+		t.Errorf("Expected default summary %q, got %q", want, session.Summary)
+	}
+}
+
+// An unreadable file is left out and reported without losing the rest
+func TestListSessionsSkipsUnreadable(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read files regardless of permissions")
+	}
+	dir := t.TempDir()
+	line := `{"type":"user","message":{"role":"user","content":"hello"}}` + "\n"
+	for _, name := range []string{"readable.jsonl", "locked.jsonl"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(line), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(dir, "locked.jsonl"), 0); err != nil {
+		t.Fatalf("Failed to lock test file: %v", err)
+	}
+
+	sessions, err := NewParser().ListSessions(dir)
+	var skipped *SkippedError
+	if !errors.As(err, &skipped) {
+		t.Fatalf("Expected a SkippedError, got %v", err)
+	}
+	if skipped.Sessions != 1 || skipped.Permission != 1 {
+		t.Errorf("Expected 1 session skipped for permissions, got %+v", skipped)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "readable" {
+		t.Errorf("Expected only the readable session, got %+v", sessions)
+	}
+}
+
+// writeManySessions fills a project directory with count small sessions,
+// the size of a heavy user's history
+func writeManySessions(tb testing.TB, count int) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	content := []byte(`{"type":"user","cwd":"/work","message":{"role":"user","content":"fix the flaky test"}}` + "\n" +
+		`{"type":"assistant","costUSD":0.01,"message":{"role":"assistant","content":"done"}}` + "\n")
+	for i := 0; i < count; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("%08d-0000-0000-0000-000000000000.jsonl", i))
+		if err := os.WriteFile(name, content, 0644); err != nil {
+			tb.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+	return dir
+}
+
+// listManyBudget is how long listing 5000 sessions may take. It is far
+// above the usual time so only a real regression trips it.
+const listManyBudget = 5 * time.Second
+
+// Startup lists every session, so thousands of files must stay quick
+func TestListSessionsManyFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes 5000 files")
+	}
+	dir := writeManySessions(t, 5000)
+
+	start := time.Now()
+	sessions, err := NewParser().ListSessions(dir)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 5000 {
+		t.Fatalf("Expected 5000 sessions, got %d", len(sessions))
+	}
+	if sessions[0].LastActive.IsZero() || sessions[0].SizeBytes == 0 {
+		t.Errorf("Expected file times and sizes, got %+v", sessions[0])
+	}
+	if elapsed > listManyBudget {
+		t.Errorf("Listing 5000 sessions took %v, over %v", elapsed, listManyBudget)
+	}
+}
+
+func BenchmarkListSessions(b *testing.B) {
+	dir := writeManySessions(b, 5000)
+	p := NewParser()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ListSessions(dir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// The concurrent listing scan gives every session its own count
+func TestListSessionsMessageCounts(t *testing.T) {
+	dir := t.TempDir()
+	line := `{"type":"user","message":{"role":"user","content":"hello"}}` + "\n"
+	for i := 1; i <= 10; i++ {
+		name := filepath.Join(dir, fmt.Sprintf("session-%d.jsonl", i))
+		content := strings.Repeat(line, i) + `{"type":"summary","summary":"not a message"}` + "\n"
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+	}
+
+	sessions, err := NewParser().ListSessions(dir)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 10 {
+		t.Fatalf("Expected 10 sessions, got %d", len(sessions))
+	}
+	for _, session := range sessions {
+		want := 0
+		fmt.Sscanf(session.ID, "session-%d", &want)
+		if session.MessageCount != want {
+			t.Errorf("%s: expected %d messages, got %d", session.ID, want, session.MessageCount)
+		}
+	}
+}
+
+func TestParseFullSessionRawMessages(t *testing.T) {
+	lines := []string{
+		`{"type":"user","message":{"role":"user","content":"one"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":"two"}}`,
+		`{"type":"user","message":{"role":"user","content":"three"}}`,
+	}
+	path := writeSession(t, lines...)
+
+	session, err := NewParserWithOptions(Options{RawMessages: 2}).ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	want := []string{lines[2], lines[1]}
+	if strings.Join(session.LastRawMessages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected the last two lines, newest first, got %q", session.LastRawMessages)
+	}
+
+	// Asking for more lines than the file has keeps them all
+	session, err = NewParserWithOptions(Options{RawMessages: 10}).ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if len(session.LastRawMessages) != 3 {
+		t.Errorf("Expected 3 raw messages, got %d", len(session.LastRawMessages))
+	}
+}
+
+// A rescan picks up content written after the file time was recorded
+func TestScanSessionReadsCurrentContent(t *testing.T) {
+	path := writeSession(t, `{"type":"user","message":{"role":"user","content":"first"}}`)
+	before, err := NewParser().ScanSession(path)
+	if err != nil {
+		t.Fatalf("ScanSession failed: %v", err)
+	}
+
+	// Rewrite the file but keep its time, as a copy preserving timestamps would
+	content := `{"type":"user","message":{"role":"user","content":"second"}}` + "\n" +
+		`{"type":"assistant","message":{"role":"assistant","content":"reply"}}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, before.LastActive, before.LastActive); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := NewParser().ScanSession(path)
+	if err != nil {
+		t.Fatalf("ScanSession failed: %v", err)
+	}
+	if after.Title != "second" || after.MessageCount != 2 || !after.LastActive.Equal(before.LastActive) {
+		t.Errorf("Unexpected rescan: %+v", after)
+	}
+	if after.ID != before.ID || after.Project != filepath.Base(filepath.Dir(path)) {
+		t.Errorf("Unexpected identity: %+v", after)
+	}
+}
+
+// A title the session was given wins over its first prompt, the latest one
+// if it was renamed more than once
+func TestListSessionsExplicitTitle(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"fix the flaky test"}}`,
+		`{"type":"custom-title","customTitle":"Flaky test hunt"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":"On it"}}`,
+		`{"type":"custom-title","customTitle":"CI flakiness"}`,
+	)
+
+	sessions, err := NewParser().ListSessions(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Title != "CI flakiness" {
+		t.Errorf("Expected the last explicit title, got %+v", sessions)
+	}
+	if sessions[0].MessageCount != 2 {
+		t.Errorf("Expected title entries not to count as messages, got %d", sessions[0].MessageCount)
+	}
+
+	// Without one the first prompt is the title
+	untitled := writeSession(t, `{"type":"user","message":{"role":"user","content":"fix the flaky test"}}`)
+	sessions, err = NewParser().ListSessions(filepath.Dir(untitled))
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Title != "fix the flaky test" {
+		t.Errorf("Expected the first prompt as title, got %+v", sessions)
+	}
+}
+
+// TitleMessages joins the opening prompts, skipping system reminders, so a
+// greeting does not stand for the whole session
+func TestListSessionsTitleMessages(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"hi there"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":"Hello"}}`,
+		`{"type":"user","message":{"role":"user","content":"<system-reminder>ignore</system-reminder>"}}`,
+		`{"type":"user","message":{"role":"user","content":"fix the\nflaky test"}}`,
+		`{"type":"user","message":{"role":"user","content":"and the docs"}}`,
+	)
+
+	for messages, want := range map[int]string{0: "hi there", 1: "hi there", 2: "hi there fix the flaky test", 5: "hi there fix the flaky test and the docs"} {
+		sessions, err := NewParserWithOptions(Options{TitleMessages: messages}).ListSessions(filepath.Dir(path))
+		if err != nil {
+			t.Fatalf("ListSessions failed: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].Title != want {
+			t.Errorf("Expected title %q from %d messages, got %+v", want, messages, sessions)
+		}
+	}
+}
+
+// Lines split from one message repeat its usage and must count once
+func TestParseFullSessionTokens(t *testing.T) {
+	usage := `"usage":{"input_tokens":10,"output_tokens":20,"cache_read_input_tokens":300,"cache_creation_input_tokens":4}`
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"hi"}}`,
+		`{"type":"assistant","message":{"id":"msg_1","content":[{"type":"text","text":"a"}],`+usage+`}}`,
+		`{"type":"assistant","message":{"id":"msg_1","content":[{"type":"tool_use","name":"Read"}],`+usage+`}}`,
+		`{"type":"assistant","message":{"id":"msg_2","content":"b",`+usage+`}}`,
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+
+	want := model.TokenUsage{Input: 20, Output: 40, CacheCreation: 8, CacheRead: 600}
+	if session.Tokens != want {
+		t.Errorf("Expected %+v, got %+v", want, session.Tokens)
+	}
+}
+
+// Files past the kept list's cap are still counted, once each
+func TestParseFullSessionFileCount(t *testing.T) {
+	var lines []string
+	for i := 0; i < maxReferencedFiles+50; i++ {
+		line := fmt.Sprintf(`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"/src/%d.go"}}]}}`, i)
+		lines = append(lines, line, line)
+	}
+	session, err := NewParser().ParseFullSession(writeSession(t, lines...))
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if len(session.ReferencedFiles) != maxReferencedFiles || session.FileCount != maxReferencedFiles+50 {
+		t.Errorf("Expected %d of %d files kept, got %d of %d", maxReferencedFiles, maxReferencedFiles+50, len(session.ReferencedFiles), session.FileCount)
+	}
+}
+
+// Turns made only of tool traffic are flagged so the viewer can fold them
+func TestParseMessagesToolTurns(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"read the file"}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Reading it"},{"type":"tool_use","name":"Read"}]}}`,
+		`{"type":"user","message":{"content":[{"type":"tool_result","content":"file body"}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Edit"}]}}`,
+	)
+
+	messages, err := NewParser().ParseMessages(path)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+
+	want := []bool{false, false, true, true}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got %d", len(want), len(messages))
+	}
+	for i, tool := range want {
+		if messages[i].Tool != tool {
+			t.Errorf("Message %d: expected Tool=%v", i, tool)
+		}
+	}
+}
+
+// Slash commands are counted whether Claude Code tagged them or they were
+// typed, while paths and ordinary prompts are not
+func TestParseFullSessionSlashCommands(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"<command-name>/compact</command-name>\n<command-message>compact</command-message>\n<command-args></command-args>"}}`,
+		`{"type":"user","message":{"role":"user","content":"/review-pr 42"}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"text","text":"<command-name>/compact</command-name>"}]}}`,
+		`{"type":"user","message":{"role":"user","content":"/Users/me/app/main.go fails to build"}}`,
+		`{"type":"user","message":{"role":"user","content":"please run /clear later"}}`,
+		`{"type":"assistant","message":{"role":"assistant","content":"/compact"}}`,
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	want := []model.SlashCommand{{Name: "/compact", Count: 2}, {Name: "/review-pr", Count: 1}}
+	if fmt.Sprint(session.SlashCommands) != fmt.Sprint(want) {
+		t.Errorf("Expected %v, got %v", want, session.SlashCommands)
+	}
+}
+
+// Multi-line prompts collapse onto one line in the fallback summary unless
+// asked to keep their breaks
+func TestParseFullSessionMultilineSummary(t *testing.T) {
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":"Why does this fail?\n\n    func main() {\n\t\tpanic(\"boom\")\n    }"}}`,
+		`{"type":"user","message":{"role":"user","content":"second\nprompt"}}`,
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	want := `Why does this fail? func main() { panic("boom") } | second prompt`
+	if session.Summary != want {
+		t.Errorf("Expected collapsed summary %q, got %q", want, session.Summary)
+	}
+
+	opts := DefaultSummaryOptions()
+	opts.KeepNewlines = true
+	session, err = NewParserWithOptions(Options{Summary: opts}).ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if want := "second\nprompt"; !strings.HasSuffix(session.Summary, want) {
+		t.Errorf("Expected the raw message kept, got %q", session.Summary)
+	}
+}
+
+// Excluded projects, by config pattern or ignore file, are left out of the
+// project list and of sessions gathered across projects
+func TestListProjectsExclude(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"-work-app", "-work-scratch-one", "-work-scratch-two", "-tmp-clone"} {
+		dir := filepath.Join(root, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		line := `{"type":"user","message":{"role":"user","content":"hi"}}`
+		if err := os.WriteFile(filepath.Join(dir, "s.jsonl"), []byte(line+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := "# clones\n-tmp-clone\n"
+	if err := os.WriteFile(filepath.Join(root, IgnoreProjectsFile), []byte(ignore), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParserWithOptions(Options{ExcludeProjects: []string{"/work/scratch"}})
+	projects, err := p.ListProjects(root)
+	if err != nil {
+		t.Fatalf("ListProjects failed: %v", err)
+	}
+	if len(projects) != 1 || projects[0].Name != "-work-app" {
+		t.Errorf("Expected only -work-app, got %+v", projects)
+	}
+	sessions, err := p.ListAllSessions(root)
+	if err != nil {
+		t.Fatalf("ListAllSessions failed: %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("Expected the sessions of one project, got %d", len(sessions))
+	}
+	if got := p.ExcludedProjects(root); got != 3 {
+		t.Errorf("Expected 3 excluded projects, got %d", got)
+	}
+}
+
+// Invalid UTF-8 in captured tool output is replaced before anything counts
+// or cuts runes
+func TestParseFullSessionInvalidUTF8(t *testing.T) {
+	path := writeSession(t,
+		"{\"type\":\"user\",\"message\":{\"role\":\"user\",\"content\":\"bad \xff\xfe bytes\"}}",
+		"{\"type\":\"user\",\"message\":{\"role\":\"user\",\"content\":\"raw \xc3( here\"}}",
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if session.MessageCount != 2 {
+		t.Errorf("Expected 2 messages, got %d", session.MessageCount)
+	}
+	if !utf8.ValidString(session.Summary) {
+		t.Errorf("Expected a valid UTF-8 summary, got %q", session.Summary)
+	}
+	for _, line := range session.LastRawMessages {
+		if !utf8.ValidString(line) {
+			t.Errorf("Expected valid UTF-8 raw lines, got %q", line)
+		}
+	}
+
+	messages, err := NewParser().ParseMessages(path)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(messages) != 2 || !strings.Contains(messages[1].Content, "raw \uFFFD( here") {
+		t.Errorf("Expected the bad byte replaced, got %+v", messages)
+	}
+}
+
+// Images are counted, shown as a placeholder in the transcript and elided
+// from raw lines
+func TestParseFullSessionImages(t *testing.T) {
+	data := strings.Repeat("iVBORw0KGgo/AAAA", 20)
+	image := `{"type":"image","source":{"type":"base64","media_type":"image/png","data":"` + data + `"}}`
+	path := writeSession(t,
+		`{"type":"user","message":{"role":"user","content":[{"type":"text","text":"what is this?"},`+image+`]}}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":[`+image+`]}]}}`,
+	)
+
+	session, err := NewParser().ParseFullSession(path)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if session.ImageCount != 2 {
+		t.Errorf("Expected 2 images, got %d", session.ImageCount)
+	}
+
+	messages, err := NewParser().ParseMessages(path)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(messages) == 0 || messages[0].Content != "what is this?\n[image]" {
+		t.Errorf("Expected an image placeholder in the transcript, got %+v", messages)
+	}
+
+	raw := ElideImages(image)
+	if strings.Contains(raw, data) || !strings.Contains(raw, `"data":"[image]"`) {
+		t.Errorf("Expected the image data elided, got %s", raw)
+	}
+	if !json.Valid([]byte(raw)) {
+		t.Errorf("Expected valid JSON after eliding, got %s", raw)
+	}
+}
+
+// Files whose recorded session ID names another file continue it, and
+// merge into one entry under the newest file when asked to
+func TestListSessionsMergeContinuations(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"first":  `{"type":"user","sessionId":"first","costUSD":1,"message":{"role":"user","content":"start the refactor"}}`,
+		"second": `{"type":"user","sessionId":"first","costUSD":2,"message":{"role":"user","content":"continue"}}`,
+		"third":  `{"type":"user","sessionId":"second","message":{"role":"user","content":"/compact"}}` + "\n" + `{"type":"assistant","sessionId":"second","message":{"role":"assistant","content":"done"}}`,
+		"other":  `{"type":"user","sessionId":"other","message":{"role":"user","content":"unrelated"}}`,
+	}
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"first", "second", "third", "other"} {
+		path := filepath.Join(dir, id+".jsonl")
+		if err := os.WriteFile(path, []byte(files[id]+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		when := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, when, when); err != nil {
+			t.Fatalf("Failed to set file time: %v", err)
+		}
+	}
+
+	if sessions, err := NewParser().ListSessions(dir); err != nil || len(sessions) != 4 {
+		t.Fatalf("Expected 4 separate sessions without merging, got %d (%v)", len(sessions), err)
+	}
+
+	p := NewParserWithOptions(Options{MergeContinuations: true})
+	sessions, err := p.ListSessions(dir)
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions after merging, got %+v", sessions)
+	}
+	var merged model.SessionInfo
+	for _, session := range sessions {
+		if len(session.Parts) > 0 {
+			merged = session
+		}
+	}
+	if merged.ID != "third" || merged.Title != "start the refactor" || merged.MessageCount != 4 || merged.CostUSD != 3 || len(merged.Parts) != 3 {
+		t.Errorf("Expected third's ID with first's title and the totals of all three, got %+v", merged)
+	}
+
+	full, err := p.ParseFullSession(merged.FilePath)
+	if err != nil {
+		t.Fatalf("ParseFullSession failed: %v", err)
+	}
+	if full.ID != "third" || full.MessageCount != 4 || len(full.SlashCommands) != 1 {
+		t.Errorf("Expected the whole conversation parsed, got %+v", full)
+	}
+
+	// Line numbers run on across the parts
+	messages, err := p.ParseMessages(merged.FilePath)
+	if err != nil {
+		t.Fatalf("ParseMessages failed: %v", err)
+	}
+	if len(messages) != 4 || messages[0].Content != "start the refactor" || messages[3].LineNumber != 4 {
+		t.Errorf("Expected 4 messages ending on line 4, got %+v", messages)
+	}
+}
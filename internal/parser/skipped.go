@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// SkippedError reports entries a listing had to leave out because they
+// could not be read. The sessions returned alongside it are still valid.
+type SkippedError struct {
+	Sessions   int   // session files that could not be read
+	Projects   int   // project directories that could not be listed
+	Permission int   // how many of the above were permission errors
+	Sample     error // the first error, for diagnosis
+}
+
+func (e *SkippedError) Error() string {
+	var parts []string
+	if e.Sessions > 0 {
+		parts = append(parts, countNoun(e.Sessions, "session"))
+	}
+	if e.Projects > 0 {
+		parts = append(parts, countNoun(e.Projects, "project"))
+	}
+	reason := "read errors"
+	if e.Permission == e.Sessions+e.Projects {
+		reason = "permission errors"
+	}
+	return fmt.Sprintf("%s skipped due to %s (%v)", strings.Join(parts, " and "), reason, e.Sample)
+}
+
+// skipSession counts a session file that could not be read
+func (e *SkippedError) skipSession(err error) {
+	e.Sessions++
+	e.note(err)
+}
+
+// skipProject counts a project directory that could not be listed
+func (e *SkippedError) skipProject(err error) {
+	e.Projects++
+	e.note(err)
+}
+
+// merge adds the skips of a nested listing
+func (e *SkippedError) merge(other *SkippedError) {
+	e.Sessions += other.Sessions
+	e.Projects += other.Projects
+	e.Permission += other.Permission
+	if e.Sample == nil {
+		e.Sample = other.Sample
+	}
+}
+
+func (e *SkippedError) note(err error) {
+	if errors.Is(err, fs.ErrPermission) {
+		e.Permission++
+	}
+	if e.Sample == nil {
+		e.Sample = err
+	}
+}
+
+// orNil returns e as an error only when something was skipped
+func (e *SkippedError) orNil() error {
+	if e.Sessions == 0 && e.Projects == 0 {
+		return nil
+	}
+	return e
+}
+
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// commandNamePattern finds the command Claude Code records for a slash
+// command the user ran, e.g. "<command-name>/compact</command-name>"
+var commandNamePattern = regexp.MustCompile(`<command-name>\s*/?([^<\s]+)\s*</command-name>`)
+
+// slashNamePattern is a typed slash command name: letters, digits, dashes,
+// underscores and the colon of namespaced commands, but no further slash,
+// so absolute paths do not count
+var slashNamePattern = regexp.MustCompile(`^/[A-Za-z][\w:-]*$`)
+
+// slashCommand returns the slash command a user message invoked, such as
+// "/compact", either from the tags Claude Code wraps it in or from content
+// that starts with one
+func slashCommand(content interface{}) (string, bool) {
+	if isToolOnly(content) {
+		return "", false
+	}
+	text := extractContent(content)
+	if match := commandNamePattern.FindStringSubmatch(text); match != nil {
+		return "/" + match[1], true
+	}
+	fields := strings.Fields(text)
+	if len(fields) > 0 && slashNamePattern.MatchString(fields[0]) {
+		return fields[0], true
+	}
+	return "", false
+}
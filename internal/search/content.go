@@ -5,29 +5,93 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"log"
+	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/davidpaquet/claude-session-browser/internal/model"
 )
 
 type ContentEngine interface {
-	SearchContent(ctx context.Context, query string, sessions []model.SessionInfo) ([]SearchResult, error)
+	SearchContent(ctx context.Context, query string, sessions []model.SessionInfo, opts SearchOptions) ([]SearchResult, error)
+}
+
+// Backend selects the program that searches session files
+type Backend string
+
+const (
+	BackendAuto    Backend = "auto" // ripgrep when installed, else Go
+	BackendRipgrep Backend = "rg"   // always ripgrep, failing without it
+	BackendGo      Backend = "go"   // built-in search, no external process
+)
+
+// ParseBackend maps a flag or config value to a Backend
+func ParseBackend(value string) (Backend, error) {
+	switch backend := Backend(value); backend {
+	case BackendAuto, BackendRipgrep, BackendGo:
+		return backend, nil
+	case "":
+		return BackendAuto, nil
+	}
+	return "", fmt.Errorf("unknown search backend %q: want rg, go or auto", value)
+}
+
+// UsesRipgrep reports whether content searches with backend run ripgrep,
+// which rejects some patterns the built-in search matches literally
+func UsesRipgrep(backend Backend) bool {
+	return backend == BackendRipgrep || (backend == BackendAuto && findRipgrep() != "")
 }
 
 type contentEngine struct {
 	maxWorkers int
 	rgPath     string
+	native     bool   // search in-process instead of running ripgrep
+	index      *Index // searched in place of the files it holds; may be nil
+
+	// Set once ripgrep's --json output turns out to be unreadable, after
+	// which every search uses the plain line output instead
+	plainMu     sync.Mutex
+	plainOutput bool
 }
 
+// NewContentEngine searches with ripgrep when it is installed and with the
+// built-in Go search otherwise
 func NewContentEngine() ContentEngine {
+	return NewContentEngineWithBackend(BackendAuto)
+}
+
+// NewContentEngineWithBackend searches with the given backend
+func NewContentEngineWithBackend(backend Backend) ContentEngine {
+	return NewContentEngineWithIndex(backend, nil)
+}
+
+// NewContentEngineWithIndex searches the files index holds in memory and
+// the rest with the given backend. An explicit ripgrep backend leaves the
+// index out, so every file is matched by the same regexp engine.
+func NewContentEngineWithIndex(backend Backend, index *Index) ContentEngine {
+	if backend == BackendRipgrep {
+		index = nil
+	}
+	rgPath := findRipgrep()
+	native := backend == BackendGo || (backend == BackendAuto && rgPath == "")
+	if rgPath == "" {
+		rgPath = "rg" // let an explicit choice fail with a clear error
+	}
 	return &contentEngine{
 		maxWorkers: 4,
-		rgPath:     findRipgrep(),
+		rgPath:     rgPath,
+		native:     native,
+		index:      index,
 	}
 }
 
+// findRipgrep returns the first ripgrep found, or "" when none is installed
 func findRipgrep() string {
 	// Try common ripgrep locations
 	paths := []string{
@@ -44,17 +108,17 @@ func findRipgrep() string {
 		}
 	}
 	
-	// Fallback to "rg" and hope it's in PATH
-	return "rg"
+	return ""
 }
 
 type searchJob struct {
 	query        string
+	opts         SearchOptions
 	session      model.SessionInfo
 	sessionIndex int
 }
 
-func (c *contentEngine) SearchContent(ctx context.Context, query string, sessions []model.SessionInfo) ([]SearchResult, error) {
+func (c *contentEngine) SearchContent(ctx context.Context, query string, sessions []model.SessionInfo, opts SearchOptions) ([]SearchResult, error) {
 	jobs := make(chan searchJob, len(sessions))
 	results := make(chan SearchResult, len(sessions))
 	
@@ -74,6 +138,7 @@ func (c *contentEngine) SearchContent(ctx context.Context, query string, session
 			return nil, ctx.Err()
 		case jobs <- searchJob{
 			query:        query,
+			opts:         opts,
 			session:      session,
 			sessionIndex: i,
 		}:
@@ -105,10 +170,11 @@ func (c *contentEngine) worker(ctx context.Context, wg *sync.WaitGroup, jobs <-c
 		case <-ctx.Done():
 			return
 		default:
-			matches, err := c.searchFile(job.query, job.session.FilePath)
+			matches, err := c.searchSession(ctx, job.query, job.session, job.opts)
 			if err == nil && len(matches) > 0 {
 				results <- SearchResult{
 					SessionID:    job.session.ID,
+					FilePath:     job.session.FilePath,
 					SessionIndex: job.sessionIndex,
 					Matches:      matches,
 					Score:        float64(len(matches)),
@@ -118,12 +184,72 @@ func (c *contentEngine) worker(ctx context.Context, wg *sync.WaitGroup, jobs <-c
 	}
 }
 
-func (c *contentEngine) searchFile(query, filePath string) ([]Match, error) {
-	cmd := exec.Command(c.rgPath,
+// searchSession searches a session's file, or every part of a merged
+// session with line numbers running on from one part to the next as the
+// parser numbers them
+func (c *contentEngine) searchSession(ctx context.Context, query string, session model.SessionInfo, opts SearchOptions) ([]Match, error) {
+	if len(session.Parts) <= 1 {
+		return c.searchFile(ctx, query, session.FilePath, opts)
+	}
+
+	var all []Match
+	offset := 0
+	for _, part := range session.Parts {
+		matches, err := c.searchFile(ctx, query, part, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range matches {
+			matches[i].LineNumber += offset
+		}
+		all = append(all, matches...)
+
+		lines, err := countLines(part)
+		if err != nil {
+			return nil, err
+		}
+		offset += lines
+	}
+	return all, nil
+}
+
+// countLines counts the lines of a file, a last line without a newline
+// included
+func countLines(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines, nil
+}
+
+func (c *contentEngine) searchFile(ctx context.Context, query, filePath string, opts SearchOptions) ([]Match, error) {
+	caseFlag := "--case-sensitive"
+	if opts.IgnoreCase {
+		caseFlag = "--ignore-case"
+	}
+	
+	if c.index != nil {
+		if lines, ok := c.index.lines(filePath); ok {
+			return searchLines(query, lines, opts), nil
+		}
+	}
+	if c.native {
+		return searchFileNative(query, filePath, opts)
+	}
+	if c.usePlainOutput() {
+		return c.searchFilePlain(ctx, query, filePath, caseFlag, opts)
+	}
+	
+	cmd := exec.CommandContext(ctx, c.rgPath,
 		"--json",
 		"--max-count", "20", // Limit matches per file
 		"--context", "1",    // Lines of context
-		"--ignore-case",     // Correct flag name
+		caseFlag,
 		query,
 		filePath,
 	)
@@ -137,7 +263,28 @@ func (c *contentEngine) searchFile(query, filePath string) ([]Match, error) {
 		return nil, err
 	}
 	
+	matches, parsed := parseJSONOutput(output, opts.Ellipsis)
+	
+	// ripgrep found something (exit 0) but printed nothing we could read:
+	// this version's --json format is not what we expect
+	if parsed == 0 && len(bytes.TrimSpace(output)) > 0 {
+		log.Printf("search: ripgrep --json output for %s was not valid JSON; the installed ripgrep's JSON format may be unsupported, falling back to plain output", filePath)
+		c.plainMu.Lock()
+		c.plainOutput = true
+		c.plainMu.Unlock()
+		return c.searchFilePlain(ctx, query, filePath, caseFlag, opts)
+	}
+	
+	return matches, nil
+}
+
+// parseJSONOutput reads ripgrep --json events into matches, one per
+// occurrence so a line holding the term several times counts each of them.
+// It also reports how many lines were valid JSON. Contexts are cut with
+// ellipsis, as in extractContext.
+func parseJSONOutput(output []byte, ellipsis string) ([]Match, int) {
 	var matches []Match
+	parsed := 0
 	scanner := bufio.NewScanner(bytes.NewReader(output))
 	
 	for scanner.Scan() {
@@ -145,56 +292,148 @@ func (c *contentEngine) searchFile(query, filePath string) ([]Match, error) {
 		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
 			continue
 		}
+		parsed++
 		
-		if result["type"] == "match" {
-			if data, ok := result["data"].(map[string]interface{}); ok {
-				match := Match{}
-				
-				// Extract line number
-				if lineNumber, ok := data["line_number"].(float64); ok {
-					match.LineNumber = int(lineNumber)
-				}
-				
-				// Extract the matched text and create context
-				if lines, ok := data["lines"].(map[string]interface{}); ok {
-					if text, ok := lines["text"].(string); ok {
-						match.Text = text
-						
-						// Extract match positions
-						var matchStartPos, matchEndPos int
-						hasPositions := false
-						
-						if submatches, ok := data["submatches"].([]interface{}); ok && len(submatches) > 0 {
-							if submatch, ok := submatches[0].(map[string]interface{}); ok {
-								if start, ok := submatch["start"].(float64); ok {
-									matchStartPos = int(start)
-									match.StartOffset = matchStartPos
-									hasPositions = true
-								}
-								if end, ok := submatch["end"].(float64); ok {
-									matchEndPos = int(end)
-									match.EndOffset = matchEndPos
-								}
-							}
-						}
-						
-						// Create context around the match
-						if hasPositions {
-							match.Context = extractContext(text, matchStartPos, matchEndPos)
-						}
-					}
-				}
-				
-				matches = append(matches, match)
+		if result["type"] != "match" {
+			continue
+		}
+		data, ok := result["data"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		
+		lineNumber := 0
+		if n, ok := data["line_number"].(float64); ok {
+			lineNumber = int(n)
+		}
+		text := ""
+		if lines, ok := data["lines"].(map[string]interface{}); ok {
+			text, _ = lines["text"].(string)
+		}
+		
+		submatches, _ := data["submatches"].([]interface{})
+		found := false
+		for _, item := range submatches {
+			submatch, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			start, okStart := submatch["start"].(float64)
+			end, okEnd := submatch["end"].(float64)
+			if !okStart || !okEnd {
+				continue
 			}
+			
+			matches = append(matches, Match{
+				Text:        text,
+				LineNumber:  lineNumber,
+				StartOffset: int(start),
+				EndOffset:   int(end),
+				Context:     extractContext(text, int(start), int(end), ellipsis),
+			})
+			found = true
+		}
+		
+		// Keep the line even when ripgrep gave no positions
+		if !found {
+			matches = append(matches, Match{Text: text, LineNumber: lineNumber})
 		}
 	}
 	
-	return matches, nil
+	return matches, parsed
+}
+
+func (c *contentEngine) usePlainOutput() bool {
+	c.plainMu.Lock()
+	defer c.plainMu.Unlock()
+	return c.plainOutput
+}
+
+// searchFilePlain runs ripgrep without --json and parses its default
+// "line:text" output, with a match per occurrence of the query.
+func (c *contentEngine) searchFilePlain(ctx context.Context, query, filePath, caseFlag string, opts SearchOptions) ([]Match, error) {
+	cmd := exec.CommandContext(ctx, c.rgPath,
+		"--line-number",
+		"--no-heading",
+		"--no-filename",
+		"--color", "never",
+		"--max-count", "20",
+		caseFlag,
+		query,
+		filePath,
+	)
+	
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return parsePlainOutput(output, query, opts), nil
 }
 
-// extractContext extracts meaningful context around a match in a JSON line
-func extractContext(text string, matchStart, matchEnd int) string {
+// CompileQuery turns a query into the regexp ripgrep would use for it, for
+// finding the matches again in text the search returned
+func CompileQuery(query string, opts SearchOptions) *regexp.Regexp {
+	pattern := query
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		// ripgrep accepts some patterns Go does not; match literally instead
+		literal := regexp.QuoteMeta(query)
+		if opts.IgnoreCase {
+			literal = "(?i)" + literal
+		}
+		re = regexp.MustCompile(literal)
+	}
+	return re
+}
+
+// parsePlainOutput reads ripgrep's "line:text" output into matches
+func parsePlainOutput(output []byte, query string, opts SearchOptions) []Match {
+	re := CompileQuery(query, opts)
+	
+	var matches []Match
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(nil, len(output)+1)
+	for scanner.Scan() {
+		number, text, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		lineNumber, err := strconv.Atoi(number)
+		if err != nil {
+			continue
+		}
+		
+		locs := re.FindAllStringIndex(text, -1)
+		if len(locs) == 0 {
+			matches = append(matches, Match{Text: text, LineNumber: lineNumber})
+			continue
+		}
+		for _, loc := range locs {
+			matches = append(matches, Match{
+				Text:        text,
+				LineNumber:  lineNumber,
+				StartOffset: loc[0],
+				EndOffset:   loc[1],
+				Context:     extractContext(text, loc[0], loc[1], opts.Ellipsis),
+			})
+		}
+	}
+	return matches
+}
+
+// extractContext extracts meaningful context around a match in a JSON line,
+// marking cuts with ellipsis ("..." when empty)
+func extractContext(text string, matchStart, matchEnd int, ellipsis string) string {
+	if ellipsis == "" {
+		ellipsis = "..."
+	}
 	// If this looks like a Claude message JSON, extract just the content
 	if strings.Contains(text, `"content":"`) {
 		contentStart := strings.Index(text, `"content":"`)
@@ -218,18 +457,20 @@ func extractContext(text string, matchStart, matchEnd int) string {
 					if contextEnd > len(content) {
 						contextEnd = len(content)
 					}
+					contextStart = runeStart(content, contextStart)
+					contextEnd = runeStart(content, contextEnd)
 					
 					// Build context with ellipsis
 					var result strings.Builder
 					if contextStart > 0 {
-						result.WriteString("...")
+						result.WriteString(ellipsis)
 					}
 					result.WriteString(content[contextStart:contextEnd])
 					if contextEnd < len(content) {
-						result.WriteString("...")
+						result.WriteString(ellipsis)
 					}
 					
-					return result.String()
+					return strings.ToValidUTF8(result.String(), "\uFFFD")
 				}
 			}
 		}
@@ -245,15 +486,27 @@ func extractContext(text string, matchStart, matchEnd int) string {
 	if contextEnd > len(text) {
 		contextEnd = len(text)
 	}
+	contextStart = runeStart(text, contextStart)
+	contextEnd = runeStart(text, contextEnd)
 	
 	var result strings.Builder
 	if contextStart > 0 {
-		result.WriteString("...")
+		result.WriteString(ellipsis)
 	}
 	result.WriteString(text[contextStart:contextEnd])
 	if contextEnd < len(text) {
-		result.WriteString("...")
+		result.WriteString(ellipsis)
 	}
 	
-	return result.String()
+	return strings.ToValidUTF8(result.String(), "\uFFFD")
+}
+
+// runeStart moves byte offset i back to the start of the character it falls
+// in, so cutting there never splits a multi-byte rune. Session lines may
+// hold invalid UTF-8, which the caller replaces after cutting.
+func runeStart(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
 }
\ No newline at end of file
@@ -1,9 +1,12 @@
 package search
 
 import (
+	"context"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func TestContextExtraction(t *testing.T) {
@@ -24,7 +27,7 @@ func TestContextExtraction(t *testing.T) {
 	}
 	defer os.Remove(tmpFile)
 	
-	matches, err := engine.searchFile("OAuth", tmpFile)
+	matches, err := engine.searchFile(context.Background(), "OAuth", tmpFile, SearchOptions{IgnoreCase: true})
 	if err != nil {
 		t.Fatalf("Search failed: %v", err)
 	}
@@ -57,4 +60,82 @@ func TestContextExtraction(t *testing.T) {
 	if !strings.Contains(matches[0].Context, "OAuth") {
 		t.Error("Context should contain the search term")
 	}
-}
\ No newline at end of file
+}
+
+func TestParsePlainOutput(t *testing.T) {
+	output := []byte("3:{\"content\":\"Set up OAuth for the API\"}\n" +
+		"not a match line\n" +
+		"12:{\"content\":\"oauth tokens expire\"}\n")
+	
+	matches := parsePlainOutput(output, "OAuth", SearchOptions{IgnoreCase: true})
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d", len(matches))
+	}
+	
+	if matches[0].LineNumber != 3 || matches[1].LineNumber != 12 {
+		t.Errorf("Unexpected line numbers: %d, %d", matches[0].LineNumber, matches[1].LineNumber)
+	}
+	first := matches[0]
+	if got := first.Text[first.StartOffset:first.EndOffset]; got != "OAuth" {
+		t.Errorf("Expected offsets to cover OAuth, got %q", got)
+	}
+	if !strings.Contains(matches[1].Context, "oauth tokens") {
+		t.Errorf("Expected context around the match, got %q", matches[1].Context)
+	}
+	
+	// Case-sensitive searches only locate exact-case matches
+	matches = parsePlainOutput([]byte("1:oauth then OAuth\n"), "OAuth", SearchOptions{})
+	if len(matches) != 1 || matches[0].StartOffset != 11 {
+		t.Errorf("Expected case-sensitive match at offset 11, got %+v", matches)
+	}
+}
+
+func TestParseJSONOutputMultipleSubmatches(t *testing.T) {
+	line := `{"content":"OAuth here, OAuth there, OAuth everywhere"}`
+	output := []byte(`{"type":"begin","data":{"path":{"text":"s.jsonl"}}}
+{"type":"match","data":{"path":{"text":"s.jsonl"},"lines":{"text":` + jsonString(line) + `},"line_number":7,"submatches":[{"match":{"text":"OAuth"},"start":12,"end":17},{"match":{"text":"OAuth"},"start":24,"end":29},{"match":{"text":"OAuth"},"start":37,"end":42}]}}
+{"type":"end","data":{}}
+`)
+	
+	matches, parsed := parseJSONOutput(output, "")
+	if parsed != 3 {
+		t.Errorf("Expected 3 parsed events, got %d", parsed)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("Expected a match per occurrence (3), got %d", len(matches))
+	}
+	
+	for i, want := range []int{12, 24, 37} {
+		match := matches[i]
+		if match.LineNumber != 7 {
+			t.Errorf("Match %d: expected line 7, got %d", i, match.LineNumber)
+		}
+		if match.StartOffset != want {
+			t.Errorf("Match %d: expected start %d, got %d", i, want, match.StartOffset)
+		}
+		if got := line[match.StartOffset:match.EndOffset]; got != "OAuth" {
+			t.Errorf("Match %d: offsets cover %q", i, got)
+		}
+		if !strings.Contains(match.Context, "OAuth") {
+			t.Errorf("Match %d: context %q misses the term", i, match.Context)
+		}
+	}
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// Contexts are cut on character boundaries and never carry invalid UTF-8
+func TestExtractContextRuneBoundaries(t *testing.T) {
+	text := strings.Repeat("😀", 40) + "needle" + strings.Repeat("😀", 40) + "\xff"
+	start := strings.Index(text, "needle")
+	got := extractContext(text, start, start+len("needle"), "")
+	if !utf8.ValidString(got) {
+		t.Errorf("Expected valid UTF-8, got %q", got)
+	}
+	if !strings.Contains(got, "needle") {
+		t.Errorf("Expected the match kept, got %q", got)
+	}
+}
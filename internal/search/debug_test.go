@@ -1,6 +1,7 @@
 package search
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"testing"
@@ -72,7 +73,7 @@ func TestSearchFileDebug(t *testing.T) {
 	}
 	defer os.Remove(tmpFile)
 	
-	matches, err := engine.searchFile("OAuth", tmpFile)
+	matches, err := engine.searchFile(context.Background(), "OAuth", tmpFile, SearchOptions{IgnoreCase: true})
 	t.Logf("Search result - Error: %v, Matches: %d", err, len(matches))
 	for i, match := range matches {
 		t.Logf("Match %d: Text=%q, Line=%d, Context=%q", i, match.Text, match.LineNumber, match.Context)
@@ -14,22 +14,40 @@ const (
 )
 
 type SearchResult struct {
-	SessionID    string
+	SessionID string
+	// FilePath identifies the session file. IDs are only unique within a
+	// project, so results spanning projects must be matched by path.
+	FilePath     string
 	SessionIndex int
 	Matches      []Match
 	Score        float64
 }
 
+// SearchOptions tunes a single search request
+type SearchOptions struct {
+	IgnoreCase bool
+	// Recent limits content search to the first Recent sessions, which the
+	// caller keeps most recently active first. 0 searches them all.
+	Recent int
+	// Ellipsis marks where a match's Context was cut from its line; empty
+	// keeps "..."
+	Ellipsis string
+}
+
 type Match struct {
 	Text        string
 	LineNumber  int
 	StartOffset int
 	EndOffset   int
 	Context     string
+	// Field is the session field a filter match is in (FieldID, FieldTitle
+	// or FieldDate), its offsets counting runes into that field alone.
+	// Content matches leave it empty.
+	Field string
 }
 
 type Engine interface {
-	Search(ctx context.Context, query string, searchType SearchType) ([]SearchResult, error)
+	Search(ctx context.Context, query string, searchType SearchType, opts SearchOptions) ([]SearchResult, error)
 	UpdateSessions(sessions []model.SessionInfo)
 }
 
@@ -40,19 +58,36 @@ type engine struct {
 }
 
 func NewEngine(sessions []model.SessionInfo) Engine {
+	return NewEngineWithBackend(sessions, BackendAuto)
+}
+
+// NewEngineWithBackend creates an engine whose content search uses the
+// given backend
+func NewEngineWithBackend(sessions []model.SessionInfo, backend Backend) Engine {
+	return NewEngineWithIndex(sessions, backend, nil)
+}
+
+// NewEngineWithIndex creates an engine whose content search reads the files
+// index holds from memory and the rest with the given backend. index may be
+// nil, and may be shared between engines.
+func NewEngineWithIndex(sessions []model.SessionInfo, backend Backend, index *Index) Engine {
 	return &engine{
 		sessions:      sessions,
 		filterEngine:  NewFilterEngine(),
-		contentEngine: NewContentEngine(),
+		contentEngine: NewContentEngineWithIndex(backend, index),
 	}
 }
 
-func (e *engine) Search(ctx context.Context, query string, searchType SearchType) ([]SearchResult, error) {
+func (e *engine) Search(ctx context.Context, query string, searchType SearchType, opts SearchOptions) ([]SearchResult, error) {
 	switch searchType {
 	case SearchTypeFilter:
 		return e.filterEngine.Filter(query, e.sessions), nil
 	case SearchTypeContent:
-		return e.contentEngine.SearchContent(ctx, query, e.sessions)
+		sessions := e.sessions
+		if opts.Recent > 0 && opts.Recent < len(sessions) {
+			sessions = sessions[:opts.Recent]
+		}
+		return e.contentEngine.SearchContent(ctx, query, sessions, opts)
 	default:
 		return []SearchResult{}, nil
 	}
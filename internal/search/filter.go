@@ -1,8 +1,9 @@
 package search
 
 import (
-	"fmt"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/davidpaquet/claude-session-browser/internal/model"
 	"github.com/sahilm/fuzzy"
@@ -10,6 +11,9 @@ import (
 
 type FilterEngine interface {
 	Filter(query string, sessions []model.SessionInfo) []SearchResult
+	// FilterText fuzzy-matches arbitrary strings. SessionIndex in each
+	// result is the index into texts.
+	FilterText(query string, texts []string) []SearchResult
 }
 
 type filterEngine struct{}
@@ -18,18 +22,38 @@ func NewFilterEngine() FilterEngine {
 	return &filterEngine{}
 }
 
+// The session fields the filter matches, each on its own so a query never
+// runs from the end of one field into the start of the next
+const (
+	FieldID    = "id"
+	FieldTitle = "title"
+	FieldDate  = "date"
+)
+
+// filterFields lists the fields in the order they are tried; a match in an
+// earlier field wins a tie with a later one
+var filterFields = []string{FieldID, FieldTitle, FieldDate}
+
+// FieldText returns the text of a session field the filter matches, which
+// a match's offsets index into
+func FieldText(session model.SessionInfo, field string) string {
+	switch field {
+	case FieldTitle:
+		return session.Title
+	case FieldDate:
+		return session.LastActive.Format("2006-01-02 15:04")
+	}
+	return session.ID
+}
+
+// sessionSource presents one field of every session to the fuzzy matcher
 type sessionSource struct {
 	sessions []model.SessionInfo
+	field    string
 }
 
 func (s sessionSource) String(i int) string {
-	session := s.sessions[i]
-	// Combine searchable fields for better matching
-	searchText := fmt.Sprintf("%s %s", 
-		session.ID,
-		session.LastActive.Format("2006-01-02 15:04"),
-	)
-	return searchText
+	return FieldText(s.sessions[i], s.field)
 }
 
 func (s sessionSource) Len() int {
@@ -43,6 +67,7 @@ func (f *filterEngine) Filter(query string, sessions []model.SessionInfo) []Sear
 		for i, session := range sessions {
 			results[i] = SearchResult{
 				SessionID:    session.ID,
+				FilePath:     session.FilePath,
 				SessionIndex: i,
 				Score:        1.0,
 			}
@@ -50,33 +75,78 @@ func (f *filterEngine) Filter(query string, sessions []model.SessionInfo) []Sear
 		return results
 	}
 
-	source := sessionSource{sessions: sessions}
-	matches := fuzzy.FindFrom(query, source)
+	// Each session keeps its best field's match
+	best := make(map[int]SearchResult)
+	for _, field := range filterFields {
+		for _, match := range fuzzy.FindFrom(query, sessionSource{sessions: sessions, field: field}) {
+			if previous, ok := best[match.Index]; ok && previous.Score >= float64(match.Score) {
+				continue
+			}
+			best[match.Index] = SearchResult{
+				SessionID:    sessions[match.Index].ID,
+				FilePath:     sessions[match.Index].FilePath,
+				SessionIndex: match.Index,
+				Score:        float64(match.Score),
+				Matches:      runeMatches(match, field),
+			}
+		}
+	}
 
-	results := make([]SearchResult, 0, len(matches))
-	for _, match := range matches {
-		// Extract match positions for highlighting
-		matchIndices := make([]Match, 0)
-		for _, idx := range match.MatchedIndexes {
-			matchIndices = append(matchIndices, Match{
-				StartOffset: idx,
-				EndOffset:   idx + 1,
-			})
+	results := make([]SearchResult, 0, len(best))
+	for _, result := range best {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].SessionIndex < results[j].SessionIndex
+	})
+	return results
+}
+
+// runeMatches turns the matcher's byte positions into one-rune matches in
+// field, so highlighting lines up past non-ASCII text
+func runeMatches(match fuzzy.Match, field string) []Match {
+	matches := make([]Match, 0, len(match.MatchedIndexes))
+	for _, idx := range match.MatchedIndexes {
+		offset := utf8.RuneCountInString(match.Str[:idx])
+		matches = append(matches, Match{
+			StartOffset: offset,
+			EndOffset:   offset + 1,
+			Field:       field,
+		})
+	}
+	return matches
+}
+
+func (f *filterEngine) FilterText(query string, texts []string) []SearchResult {
+	if query == "" {
+		results := make([]SearchResult, len(texts))
+		for i := range texts {
+			results[i] = SearchResult{SessionIndex: i, Score: 1.0}
 		}
+		return results
+	}
 
+	matches := fuzzy.Find(query, texts)
+
+	results := make([]SearchResult, 0, len(matches))
+	for _, match := range matches {
 		results = append(results, SearchResult{
-			SessionID:    sessions[match.Index].ID,
 			SessionIndex: match.Index,
 			Score:        float64(match.Score),
-			Matches:      matchIndices,
+			Matches:      runeMatches(match, ""),
 		})
 	}
 
 	return results
 }
 
-// HighlightText applies highlighting to matched characters
-func HighlightText(text string, indices []int, highlightStyle func(string) string) string {
+// HighlightText applies highlighting to matched characters. highlight is
+// called with each character's position in indices, so callers can style
+// matches differently.
+func HighlightText(text string, indices []int, highlight func(match int, s string) string) string {
 	if len(indices) == 0 {
 		return text
 	}
@@ -85,17 +155,17 @@ func HighlightText(text string, indices []int, highlightStyle func(string) strin
 	runes := []rune(text)
 	var result strings.Builder
 
-	// Create a map for faster lookup
-	indexMap := make(map[int]bool)
-	for _, idx := range indices {
+	// Map rune positions to their match for faster lookup
+	indexMap := make(map[int]int)
+	for match, idx := range indices {
 		if idx < len(runes) {
-			indexMap[idx] = true
+			indexMap[idx] = match
 		}
 	}
 
 	for i, r := range runes {
-		if indexMap[i] {
-			result.WriteString(highlightStyle(string(r)))
+		if match, ok := indexMap[i]; ok {
+			result.WriteString(highlight(match, string(r)))
 		} else {
 			result.WriteRune(r)
 		}
@@ -0,0 +1,110 @@
+package search
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// maxIndexBytes caps the session content an Index holds; files past it are
+// searched from disk as without one
+const maxIndexBytes = 256 << 20
+
+// Index keeps session files' lines in memory so repeated content searches
+// read no files and start no processes. Each entry is checked against its
+// file's size and modification time when searched and read again when the
+// session has changed since.
+type Index struct {
+	mu    sync.RWMutex
+	files map[string]indexedFile
+	bytes int64
+}
+
+type indexedFile struct {
+	size    int64
+	modTime time.Time
+	lines   []string
+}
+
+// NewIndex returns an empty index
+func NewIndex() *Index {
+	return &Index{files: make(map[string]indexedFile)}
+}
+
+// Warm reads every file of sessions into the index until ctx is done or
+// the index is full, and returns how many files it holds
+func (x *Index) Warm(ctx context.Context, sessions []model.SessionInfo) int {
+	for _, session := range sessions {
+		for _, path := range sessionFiles(session) {
+			if ctx.Err() != nil {
+				return x.Len()
+			}
+			x.lines(path)
+		}
+	}
+	return x.Len()
+}
+
+// Len returns how many files the index holds
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.files)
+}
+
+// lines returns the file's lines, reading them into the index when the file
+// is new or changed. ok is false when the file cannot be read or does not
+// fit, and the caller should search it on disk.
+func (x *Index) lines(path string) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	x.mu.Lock()
+	entry, ok := x.files[path]
+	if ok && entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+		x.mu.Unlock()
+		return entry.lines, true
+	}
+	if ok {
+		// Stale: drop it, and index it afresh below if it still fits
+		delete(x.files, path)
+		x.bytes -= entry.size
+	}
+	full := x.bytes+info.Size() > maxIndexBytes
+	x.mu.Unlock()
+	if full {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	x.mu.Lock()
+	if previous, ok := x.files[path]; ok {
+		x.bytes -= previous.size
+	}
+	x.files[path] = indexedFile{size: int64(len(data)), modTime: info.ModTime(), lines: lines}
+	x.bytes += int64(len(data))
+	x.mu.Unlock()
+	return lines, true
+}
+
+// sessionFiles lists the files holding a session, in order
+func sessionFiles(session model.SessionInfo) []string {
+	if len(session.Parts) > 1 {
+		return session.Parts
+	}
+	return []string{session.FilePath}
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+)
+
+// maxMatchLines caps matching lines per file, like ripgrep's --max-count
+const maxMatchLines = 20
+
+// searchFileNative is the built-in backend: it reads the file line by line
+// and reports every occurrence of the query, as ripgrep's output is parsed
+func searchFileNative(query, filePath string, opts SearchOptions) ([]Match, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	re := CompileQuery(query, opts)
+	reader := bufio.NewReaderSize(file, 64*1024)
+
+	var matches []Match
+	matchedLines := 0
+	for lineNumber := 1; matchedLines < maxMatchLines; lineNumber++ {
+		// ReadBytes has no line length limit, unlike bufio.Scanner
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			found := lineMatches(re, string(bytes.TrimRight(line, "\r\n")), lineNumber, opts)
+			if len(found) > 0 {
+				matchedLines++
+				matches = append(matches, found...)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// searchLines searches lines already in memory, as searchFileNative
+// searches a file
+func searchLines(query string, lines []string, opts SearchOptions) []Match {
+	re := CompileQuery(query, opts)
+
+	var matches []Match
+	matchedLines := 0
+	for i, text := range lines {
+		if matchedLines == maxMatchLines {
+			break
+		}
+		found := lineMatches(re, text, i+1, opts)
+		if len(found) > 0 {
+			matchedLines++
+			matches = append(matches, found...)
+		}
+	}
+	return matches
+}
+
+// lineMatches reports every occurrence of re in one line
+func lineMatches(re *regexp.Regexp, text string, lineNumber int, opts SearchOptions) []Match {
+	var matches []Match
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		matches = append(matches, Match{
+			Text:        text,
+			LineNumber:  lineNumber,
+			StartOffset: loc[0],
+			EndOffset:   loc[1],
+			Context:     extractContext(text, loc[0], loc[1], opts.Ellipsis),
+		})
+	}
+	return matches
+}
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/davidpaquet/claude-session-browser/internal/model"
 )
@@ -58,7 +59,7 @@ func TestContentSearch(t *testing.T) {
 
 	// Test 1: Search for "OAuth"
 	t.Run("Search for OAuth", func(t *testing.T) {
-		results, err := engine.SearchContent(ctx, "OAuth", sessions)
+		results, err := engine.SearchContent(ctx, "OAuth", sessions, SearchOptions{IgnoreCase: true})
 		if err != nil {
 			t.Errorf("Search failed: %v", err)
 		}
@@ -72,7 +73,7 @@ func TestContentSearch(t *testing.T) {
 
 	// Test 2: Search for "webpack"
 	t.Run("Search for webpack", func(t *testing.T) {
-		results, err := engine.SearchContent(ctx, "webpack", sessions)
+		results, err := engine.SearchContent(ctx, "webpack", sessions, SearchOptions{IgnoreCase: true})
 		if err != nil {
 			t.Errorf("Search failed: %v", err)
 		}
@@ -86,7 +87,7 @@ func TestContentSearch(t *testing.T) {
 
 	// Test 3: Search for term in both files
 	t.Run("Search across multiple files", func(t *testing.T) {
-		results, err := engine.SearchContent(ctx, "help", sessions)
+		results, err := engine.SearchContent(ctx, "help", sessions, SearchOptions{IgnoreCase: true})
 		if err != nil {
 			t.Errorf("Search failed: %v", err)
 		}
@@ -97,7 +98,7 @@ func TestContentSearch(t *testing.T) {
 
 	// Test 4: Search for non-existent term
 	t.Run("Search for non-existent term", func(t *testing.T) {
-		results, err := engine.SearchContent(ctx, "nonexistentterm", sessions)
+		results, err := engine.SearchContent(ctx, "nonexistentterm", sessions, SearchOptions{IgnoreCase: true})
 		if err != nil {
 			t.Errorf("Search failed: %v", err)
 		}
@@ -123,4 +124,210 @@ func TestFindRipgrep(t *testing.T) {
 	
 	t.Logf("Found ripgrep at: %s", rgPath)
 	t.Logf("Version: %s", string(output))
-}
\ No newline at end of file
+}
+
+// The Go backend reports each occurrence and honors case sensitivity
+func TestNativeBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	content := `{"type":"user","content":"OAuth or oauth?"}
+{"type":"assistant","content":"nothing here"}
+{"type":"user","content":"OAuth again"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write test file: %v", err)
+	}
+	sessions := []model.SessionInfo{{ID: "session", FilePath: path}}
+	engine := NewContentEngineWithBackend(BackendGo)
+
+	results, err := engine.SearchContent(context.Background(), "OAuth", sessions, SearchOptions{IgnoreCase: true})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Matches) != 3 {
+		t.Fatalf("Expected 3 matches ignoring case, got %+v", results)
+	}
+	if results[0].Matches[2].LineNumber != 3 {
+		t.Errorf("Expected last match on line 3, got %d", results[0].Matches[2].LineNumber)
+	}
+
+	results, err = engine.SearchContent(context.Background(), "OAuth", sessions, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Matches) != 2 {
+		t.Errorf("Expected 2 case-sensitive matches, got %+v", results)
+	}
+}
+
+// Sessions of different projects can share an ID; their results must not
+func TestResultsKeepFilePath(t *testing.T) {
+	root := t.TempDir()
+	var sessions []model.SessionInfo
+	for _, project := range []string{"-work-a", "-work-b"} {
+		path := filepath.Join(root, project, "same-id.jsonl")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := `{"type":"user","content":"deploy ` + project + `"}` + "\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write test file: %v", err)
+		}
+		sessions = append(sessions, model.SessionInfo{ID: "same-id", FilePath: path, Project: project})
+	}
+
+	results, err := NewContentEngineWithBackend(BackendGo).SearchContent(context.Background(), "deploy", sessions, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected a result per session, got %+v", results)
+	}
+	for _, result := range results {
+		if result.FilePath != sessions[result.SessionIndex].FilePath {
+			t.Errorf("Result for %s carries path %s", sessions[result.SessionIndex].FilePath, result.FilePath)
+		}
+	}
+
+	for _, result := range NewFilterEngine().Filter("same", sessions) {
+		if result.FilePath != sessions[result.SessionIndex].FilePath {
+			t.Errorf("Filter result for %s carries path %s", sessions[result.SessionIndex].FilePath, result.FilePath)
+		}
+	}
+}
+
+// A merged session is searched in all of its parts, numbering lines on
+// from one part to the next
+func TestSearchMergedParts(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.jsonl")
+	second := filepath.Join(dir, "second.jsonl")
+	if err := os.WriteFile(first, []byte(`{"content":"deploy staging"}`+"\n"+`{"content":"wait"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte(`{"content":"deploy prod"}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sessions := []model.SessionInfo{{ID: "second", FilePath: second, Parts: []string{first, second}}}
+
+	results, err := NewContentEngineWithBackend(BackendGo).SearchContent(context.Background(), "deploy", sessions, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || len(results[0].Matches) != 2 {
+		t.Fatalf("Expected both parts matched, got %+v", results)
+	}
+	if line := results[0].Matches[1].LineNumber; line != 3 {
+		t.Errorf("Expected the second part's match on line 3, got %d", line)
+	}
+}
+
+// Queries Go cannot compile fall back to a literal match that keeps the
+// search's case sensitivity
+func TestCompileQueryLiteralFallback(t *testing.T) {
+	if re := CompileQuery("a(?<b", SearchOptions{}); re.MatchString("A(?<B") || !re.MatchString("x a(?<b") {
+		t.Errorf("Expected a case-sensitive literal match, got %s", re)
+	}
+	if re := CompileQuery("a(?<b", SearchOptions{IgnoreCase: true}); !re.MatchString("A(?<B") {
+		t.Errorf("Expected a case-insensitive literal match, got %s", re)
+	}
+}
+
+func TestParseBackend(t *testing.T) {
+	for value, want := range map[string]Backend{"": BackendAuto, "auto": BackendAuto, "rg": BackendRipgrep, "go": BackendGo} {
+		if got, err := ParseBackend(value); err != nil || got != want {
+			t.Errorf("ParseBackend(%q) = %q, %v; want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseBackend("grep"); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
+
+// Filter queries match within one field, and match offsets count runes into
+// the field they are in
+func TestFilterFieldAware(t *testing.T) {
+	sessions := []model.SessionInfo{
+		{ID: "abc", Title: "deploy"},
+		{ID: "xyz", Title: "café bar"},
+	}
+	filter := NewFilterEngine()
+
+	if results := filter.Filter("cde", sessions); len(results) != 0 {
+		t.Errorf("Expected no match across the ID and title, got %+v", results)
+	}
+
+	results := filter.Filter("bar", sessions)
+	if len(results) != 1 || results[0].SessionIndex != 1 {
+		t.Fatalf("Expected the café session, got %+v", results)
+	}
+	for i, match := range results[0].Matches {
+		if match.Field != FieldTitle || match.StartOffset != 5+i {
+			t.Errorf("Expected title rune %d, got %+v", 5+i, match)
+		}
+	}
+
+	texts := filter.FilterText("x", []string{"/home/é/x"})
+	if len(texts) != 1 || texts[0].Matches[0].StartOffset != 8 {
+		t.Errorf("Expected the match at rune 8, got %+v", texts)
+	}
+}
+
+// An index answers searches from memory, whichever backend auto picks, and
+// reads a file again once it changes
+func TestIndexSearch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	write := func(content string, modTime time.Time) {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	then := time.Now().Add(-time.Hour)
+	write("{\"content\":\"deploy staging\"}\r\n{\"content\":\"wait\"}\n", then)
+	sessions := []model.SessionInfo{{ID: "session", FilePath: path}}
+
+	index := NewIndex()
+	if files := index.Warm(context.Background(), sessions); files != 1 {
+		t.Fatalf("Expected the session indexed, got %d files", files)
+	}
+	engine := NewContentEngineWithIndex(BackendAuto, index)
+	search := func(query string) []SearchResult {
+		results, err := engine.SearchContent(context.Background(), query, sessions, SearchOptions{})
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		return results
+	}
+
+	results := search("staging")
+	if len(results) != 1 || results[0].Matches[0].LineNumber != 1 || results[0].Matches[0].Text != `{"content":"deploy staging"}` {
+		t.Fatalf("Expected the first line matched, got %+v", results)
+	}
+
+	// Same size and time: the index still holds the old lines
+	write("{\"content\":\"deploy preprod\"}\r\n{\"content\":\"wait\"}\n", then)
+	if results := search("staging"); len(results) != 1 {
+		t.Errorf("Expected the indexed content searched, got %+v", results)
+	}
+
+	write("{\"content\":\"deploy preprod\"}\r\n{\"content\":\"wait\"}\n", time.Now())
+	if results := search("staging"); len(results) != 0 {
+		t.Errorf("Expected the changed file read again, got %+v", results)
+	}
+	if results := search("preprod"); len(results) != 1 {
+		t.Errorf("Expected the new content found, got %+v", results)
+	}
+}
+
+// Asking for ripgrep searches every file with it, indexed or not
+func TestIndexSkippedForRipgrep(t *testing.T) {
+	index := NewIndex()
+	if engine := NewContentEngineWithIndex(BackendRipgrep, index).(*contentEngine); engine.index != nil || engine.native {
+		t.Error("Expected an explicit ripgrep backend to leave the index out")
+	}
+	if engine := NewContentEngineWithIndex(BackendGo, index).(*contentEngine); engine.index != index {
+		t.Error("Expected the Go backend to search the index")
+	}
+}
@@ -0,0 +1,254 @@
+// Package server serves the sessions under a Claude projects directory as
+// HTML pages, for reading and searching them in a web browser
+package server
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// DefaultAddr only accepts connections from this machine. Session
+// transcripts can hold secrets, so listening further is left to the user.
+const DefaultAddr = "127.0.0.1:7878"
+
+// Options adjusts how a Server searches and who it answers
+type Options struct {
+	// IgnoreCase makes ?q= searches case-insensitive
+	IgnoreCase bool
+
+	// LocalOnly refuses requests addressed to any host but this machine.
+	// Listening on loopback alone does not stop DNS rebinding, where a web
+	// page points its own host name at 127.0.0.1 to read the responses.
+	LocalOnly bool
+}
+
+// Server renders the projects, sessions and transcripts under rootDir
+type Server struct {
+	rootDir string
+	parser  *parser.Parser
+	search  search.ContentEngine
+	opts    Options
+}
+
+// New creates a server for the projects under rootDir, parsing sessions
+// with p and searching them with backend
+func New(rootDir string, p *parser.Parser, backend search.Backend, opts Options) *Server {
+	return &Server{
+		rootDir: rootDir,
+		parser:  p,
+		search:  search.NewContentEngineWithBackend(backend),
+		opts:    opts,
+	}
+}
+
+// Handler routes the index of projects, each project's session list (with
+// ?q= for a content search) and each session's transcript
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /projects/{project}/{$}", s.handleProject)
+	mux.HandleFunc("GET /projects/{project}/{id}", s.handleSession)
+	if !s.opts.LocalOnly {
+		return mux
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !localHost(r.Host) {
+			http.Error(w, "Forbidden: unexpected Host "+r.Host, http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// localHost reports whether a request's Host header names this machine
+func localHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = strings.Trim(hostport, "[]")
+	}
+	switch strings.ToLower(host) {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// IsLoopback reports whether addr only listens on this machine
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	projects, err := s.parser.ListProjects(s.rootDir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	type projectRow struct {
+		Name, Path, LastActive string
+		Sessions               int
+	}
+	var rows []projectRow
+	for _, project := range projects {
+		rows = append(rows, projectRow{
+			Name:       project.Name,
+			Path:       model.ShortenHome(model.DecodeProjectPath(project.Name)),
+			LastActive: project.LastActive.Local().Format("2006-01-02 15:04"),
+			Sessions:   project.SessionCount,
+		})
+	}
+	render(w, indexTemplate, rows)
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	dir, ok := s.projectDir(r.PathValue("project"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	sessions, err := s.parser.ListSessions(dir)
+	var skipped *parser.SkippedError
+	if err != nil && !errors.As(err, &skipped) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+
+	type sessionRow struct {
+		ID, Title, LastActive string
+		Messages              int
+		Matches               []string
+	}
+	data := struct {
+		Project, Path, Query string
+		Sessions             []sessionRow
+		Error                string
+	}{
+		Project: r.PathValue("project"),
+		Path:    model.ShortenHome(model.DecodeProjectPath(r.PathValue("project"))),
+		Query:   r.URL.Query().Get("q"),
+	}
+
+	// A query narrows the list to sessions whose content matches, with
+	// the matching lines under each
+	matches := make(map[string][]string)
+	if data.Query != "" {
+		results, err := s.search.SearchContent(r.Context(), data.Query, sessions, search.SearchOptions{IgnoreCase: s.opts.IgnoreCase})
+		if err != nil {
+			data.Error = err.Error()
+		}
+		for _, result := range results {
+			var lines []string
+			for _, match := range result.Matches {
+				text := match.Context
+				if text == "" {
+					text = strings.TrimSpace(match.Text)
+				}
+				lines = append(lines, text)
+			}
+			matches[result.FilePath] = lines
+		}
+	}
+
+	for _, session := range sessions {
+		if data.Query != "" && matches[session.FilePath] == nil {
+			continue
+		}
+		title := session.Title
+		if title == "" {
+			title = session.ID
+		}
+		data.Sessions = append(data.Sessions, sessionRow{
+			ID:         session.ID,
+			Title:      title,
+			LastActive: session.LastActive.Local().Format("2006-01-02 15:04"),
+			Messages:   session.MessageCount,
+			Matches:    matches[session.FilePath],
+		})
+	}
+	render(w, projectTemplate, data)
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	dir, ok := s.projectDir(r.PathValue("project"))
+	id := r.PathValue("id")
+	if !ok || !safeName(id) {
+		http.NotFound(w, r)
+		return
+	}
+	path := filepath.Join(dir, id+".jsonl")
+	if _, err := os.Stat(path); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	session, err := s.parser.ParseFullSession(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	messages, err := s.parser.ParseMessages(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page, err := export.HTML(session, messages)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(page))
+}
+
+// projectDir resolves a project name to its directory, refusing anything
+// that is not a directory right under the root
+func (s *Server) projectDir(name string) (string, bool) {
+	if !safeName(name) {
+		return "", false
+	}
+	dir := filepath.Join(s.rootDir, name)
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return "", false
+	}
+	return dir, true
+}
+
+// safeName reports whether name is a single path element, so it cannot
+// reach outside the directory it is joined to
+func safeName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// render writes a page, logging failures that happen once the response
+// has started
+func render(w http.ResponseWriter, t *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := t.Execute(w, data); err != nil {
+		log.Printf("server: rendering %s: %v", t.Name(), err)
+	}
+}
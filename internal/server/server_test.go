@@ -0,0 +1,143 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return newTestServerWith(t, Options{IgnoreCase: true, LocalOnly: true})
+}
+
+func newTestServerWith(t *testing.T, opts Options) *httptest.Server {
+	t.Helper()
+	root := t.TempDir()
+	project := filepath.Join(root, "-work-api")
+	if err := os.Mkdir(project, 0755); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	sessions := map[string]string{
+		"a1b2": `{"type":"user","message":{"role":"user","content":"fix the <b>login</b> bug"}}`,
+		"c3d4": `{"type":"user","message":{"role":"user","content":"add pagination"}}`,
+	}
+	for id, line := range sessions {
+		if err := os.WriteFile(filepath.Join(project, id+".jsonl"), []byte(line+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write session: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.jsonl"), []byte("{}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	srv := httptest.NewServer(New(root, parser.NewParser(), search.BackendGo, opts).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestServesProjectsSessionsAndSearch(t *testing.T) {
+	srv := newTestServer(t)
+
+	if status, body := get(t, srv.URL+"/"); status != http.StatusOK || !strings.Contains(body, `href="/projects/-work-api/"`) {
+		t.Errorf("Expected the index to link the project, got %d: %s", status, body)
+	}
+	if _, body := get(t, srv.URL+"/projects/-work-api/"); !strings.Contains(body, "/projects/-work-api/a1b2") || !strings.Contains(body, "/projects/-work-api/c3d4") {
+		t.Errorf("Expected both sessions listed, got %s", body)
+	}
+	if _, body := get(t, srv.URL+"/projects/-work-api/?q=PAGINATION"); strings.Contains(body, "a1b2") || !strings.Contains(body, "c3d4") {
+		t.Errorf("Expected only the matching session, got %s", body)
+	}
+
+	// Transcripts reuse the HTML export, escaped
+	status, body := get(t, srv.URL+"/projects/-work-api/a1b2")
+	if status != http.StatusOK || !strings.Contains(body, "&lt;b&gt;login&lt;/b&gt;") {
+		t.Errorf("Expected the escaped transcript, got %d: %s", status, body)
+	}
+}
+
+// Names that could reach outside the projects root are not found
+func TestRejectsPathsOutsideRoot(t *testing.T) {
+	srv := newTestServer(t)
+	for _, path := range []string{
+		"/projects/-work-api/missing",
+		"/projects/nope/",
+		"/projects/..%2f/secret",
+		"/projects/-work-api/..%2f..%2fsecret",
+	} {
+		if status, _ := get(t, srv.URL+path); status != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, status)
+		}
+	}
+}
+
+// Case-sensitive servers only match the query as typed
+func TestSearchFollowsIgnoreCase(t *testing.T) {
+	srv := newTestServerWith(t, Options{})
+	if _, body := get(t, srv.URL+"/projects/-work-api/?q=PAGINATION"); strings.Contains(body, "c3d4") {
+		t.Errorf("Expected no match for the wrong case, got %s", body)
+	}
+	if _, body := get(t, srv.URL+"/projects/-work-api/?q=pagination"); !strings.Contains(body, "c3d4") {
+		t.Errorf("Expected a match for the same case, got %s", body)
+	}
+}
+
+// A local server refuses requests for other host names, as a DNS
+// rebinding page would send
+func TestRejectsForeignHost(t *testing.T) {
+	srv := newTestServer(t)
+	for host, want := range map[string]int{
+		"evil.example:7878": http.StatusForbidden,
+		"evil.example":      http.StatusForbidden,
+		"localhost:7878":    http.StatusOK,
+		"[::1]:7878":        http.StatusOK,
+		"127.0.0.1":         http.StatusOK,
+	} {
+		req, err := http.NewRequest("GET", srv.URL+"/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Host = host
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET with Host %s failed: %v", host, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("Host %s: expected %d, got %d", host, want, resp.StatusCode)
+		}
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:7878": true,
+		"localhost:80":   true,
+		"[::1]:7878":     true,
+		":7878":          false,
+		"0.0.0.0:7878":   false,
+		"192.168.1.2:80": false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
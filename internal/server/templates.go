@@ -0,0 +1,62 @@
+package server
+
+import "html/template"
+
+// pageStyle matches the look of the HTML export
+const pageStyle = `<style>
+body { font-family: -apple-system, BlinkMacSystemFont, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1F2937; }
+h1 { color: #7C3AED; font-size: 1.4rem; }
+a { color: #7C3AED; text-decoration: none; }
+a:hover { text-decoration: underline; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .35rem .75rem .35rem 0; vertical-align: top; }
+th { color: #6B7280; font-weight: 600; border-bottom: 1px solid #E5E7EB; }
+.muted { color: #6B7280; }
+.match { color: #6B7280; font-size: .85rem; margin: .15rem 0 0 1rem; }
+.error { color: #EF4444; }
+input[type=search] { width: 60%; padding: .3rem .5rem; }
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Claude Sessions</title>
+` + pageStyle + `
+</head>
+<body>
+<h1>Projects</h1>
+<table>
+<tr><th>Project</th><th>Sessions</th><th>Last active</th></tr>
+{{range .}}<tr><td><a href="/projects/{{.Name}}/">{{.Path}}</a></td><td>{{.Sessions}}</td><td class="muted">{{.LastActive}}</td></tr>
+{{else}}<tr><td colspan="3" class="muted">No sessions found</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+var projectTemplate = template.Must(template.New("project").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Path}}</title>
+` + pageStyle + `
+</head>
+<body>
+<p><a href="/">← Projects</a></p>
+<h1>{{.Path}}</h1>
+<form method="get">
+<input type="search" name="q" value="{{.Query}}" placeholder="Search session content (regular expression)">
+<button type="submit">Search</button>
+{{if .Query}}<a href="?">Clear</a>{{end}}
+</form>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<table>
+<tr><th>Session</th><th>Messages</th><th>Last active</th></tr>
+{{range .Sessions}}<tr><td><a href="/projects/{{$.Project}}/{{.ID}}">{{.Title}}</a>
+{{range .Matches}}<div class="match">{{.}}</div>{{end}}</td><td>{{.Messages}}</td><td class="muted">{{.LastActive}}</td></tr>
+{{else}}<tr><td colspan="3" class="muted">{{if .Query}}No session matches '{{.Query}}'{{else}}No sessions{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
@@ -0,0 +1,114 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+)
+
+// Backend publishes an exported document and returns where it can be opened
+type Backend interface {
+	// Name is shown in status messages
+	Name() string
+	// Share stores content under filename and returns a URL or local path
+	Share(ctx context.Context, filename string, content []byte) (string, error)
+}
+
+// NewBackend picks the backend described by the config. Uploading only
+// happens when a gist backend and a token are both configured; everything
+// else falls back to writing a local file.
+func NewBackend(cfg config.ShareConfig) Backend {
+	if cfg.Backend == "gist" && cfg.GitHubToken != "" {
+		return &GistBackend{
+			Token:  cfg.GitHubToken,
+			Public: cfg.Public,
+			Client: &http.Client{Timeout: 30 * time.Second},
+		}
+	}
+
+	dir := cfg.OutputDir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "claude-session-browser")
+	}
+	return &FileBackend{Dir: dir}
+}
+
+// FileBackend writes exports to a local directory
+type FileBackend struct {
+	Dir string
+}
+
+func (f *FileBackend) Name() string {
+	return "file"
+}
+
+func (f *FileBackend) Share(ctx context.Context, filename string, content []byte) (string, error) {
+	if err := os.MkdirAll(f.Dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(f.Dir, filename)
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// gistAPI is the GitHub endpoint for creating gists
+const gistAPI = "https://api.github.com/gists"
+
+// GistBackend uploads exports as GitHub gists
+type GistBackend struct {
+	Token  string
+	Public bool
+	Client *http.Client
+}
+
+func (g *GistBackend) Name() string {
+	return "gist"
+}
+
+func (g *GistBackend) Share(ctx context.Context, filename string, content []byte) (string, error) {
+	payload := map[string]interface{}{
+		"description": "Claude session " + filename,
+		"public":      g.Public,
+		"files": map[string]interface{}{
+			filename: map[string]string{"content": string(content)},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gistAPI, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := g.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("gist upload failed: %s", resp.Status)
+	}
+
+	var result struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	return result.HTMLURL, nil
+}
@@ -0,0 +1,156 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// State is what the app remembers between runs about the user's activity,
+// as opposed to the preferences in config
+type State struct {
+	path string
+
+	// Resumed maps session IDs to when their resume command was last
+	// copied or launched
+	Resumed map[string]time.Time `json:"resumed"`
+
+	// Viewed maps session IDs to their message count when last opened, so
+	// sessions that grew since can be flagged
+	Viewed map[string]int `json:"viewed"`
+
+	// Opened maps session IDs to when their details were last opened in
+	// the browser, for the recently viewed sort
+	Opened map[string]time.Time `json:"opened"`
+
+	// Notes maps session IDs to the user's freeform note about them
+	Notes map[string]string `json:"notes"`
+
+	// PickerSort is the order last chosen in the project picker
+	PickerSort string `json:"pickerSort,omitempty"`
+}
+
+// DefaultPath returns the default state file location, next to the config
+// file (e.g. ~/.config/claude-session-browser/state.json)
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "claude-session-browser", "state.json")
+}
+
+// Load reads the state file at path. A missing file gives an empty state;
+// an empty path gives one that is never written.
+func Load(path string) (*State, error) {
+	s := &State{path: path, Resumed: map[string]time.Time{}, Viewed: map[string]int{}, Opened: map[string]time.Time{}, Notes: map[string]string{}}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, err
+	}
+	if s.Resumed == nil {
+		s.Resumed = map[string]time.Time{}
+	}
+	if s.Viewed == nil {
+		s.Viewed = map[string]int{}
+	}
+	if s.Opened == nil {
+		s.Opened = map[string]time.Time{}
+	}
+	if s.Notes == nil {
+		s.Notes = map[string]string{}
+	}
+	return s, nil
+}
+
+// MarkResumed records that the session was resumed at t and saves
+func (s *State) MarkResumed(sessionID string, t time.Time) error {
+	s.Resumed[sessionID] = t
+	return s.Save()
+}
+
+// MarkViewed records the session's message count as seen and saves,
+// skipping the write when nothing changed
+func (s *State) MarkViewed(sessionID string, messages int) error {
+	if seen, ok := s.Viewed[sessionID]; ok && seen == messages {
+		return nil
+	}
+	s.Viewed[sessionID] = messages
+	return s.Save()
+}
+
+// MarkOpened records that the session's details were opened at t. This
+// changes on every step through the list, so it is only kept in memory
+// until the next Save.
+func (s *State) MarkOpened(sessionID string, t time.Time) {
+	s.Opened[sessionID] = t
+}
+
+// PruneOpened forgets when sessions were opened for every one exists
+// reports gone, so the file does not grow with deleted sessions
+func (s *State) PruneOpened(exists func(sessionID string) bool) {
+	for id := range s.Opened {
+		if !exists(id) {
+			delete(s.Opened, id)
+		}
+	}
+}
+
+// Unread returns how many messages the session gained since it was last
+// viewed. Sessions never viewed have nothing unread.
+func (s *State) Unread(sessionID string, messages int) int {
+	seen, ok := s.Viewed[sessionID]
+	if !ok || messages <= seen {
+		return 0
+	}
+	return messages - seen
+}
+
+// SetNote attaches a note to the session and saves. A blank note removes
+// it.
+func (s *State) SetNote(sessionID, note string) error {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		delete(s.Notes, sessionID)
+	} else {
+		s.Notes[sessionID] = note
+	}
+	return s.Save()
+}
+
+// SetPickerSort remembers the project picker's order and saves
+func (s *State) SetPickerSort(order string) error {
+	if s.PickerSort == order {
+		return nil
+	}
+	s.PickerSort = order
+	return s.Save()
+}
+
+// Save writes the state file
+func (s *State) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
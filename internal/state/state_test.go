@@ -0,0 +1,163 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMarkResumedPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "state.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	when := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	if err := s.MarkResumed("abc", when); err != nil {
+		t.Fatalf("MarkResumed failed: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.Resumed["abc"]; !got.Equal(when) {
+		t.Errorf("Expected resumed at %v, got %v", when, got)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	s, err := Load(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("Expected no error for a missing file, got %v", err)
+	}
+	if len(s.Resumed) != 0 {
+		t.Errorf("Expected empty state, got %v", s.Resumed)
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err == nil {
+		t.Error("Expected an error for a corrupt file")
+	}
+	// Still usable so the app can carry on
+	if s == nil || s.Resumed == nil {
+		t.Error("Expected a usable empty state alongside the error")
+	}
+}
+
+func TestUnreadSinceViewed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if got := s.Unread("abc", 10); got != 0 {
+		t.Errorf("Expected a never-viewed session to have nothing unread, got %d", got)
+	}
+	if err := s.MarkViewed("abc", 4); err != nil {
+		t.Fatalf("MarkViewed failed: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.Unread("abc", 10); got != 6 {
+		t.Errorf("Expected 6 unread messages, got %d", got)
+	}
+	if got := reloaded.Unread("abc", 4); got != 0 {
+		t.Errorf("Expected nothing unread at the viewed count, got %d", got)
+	}
+}
+
+func TestSetNotePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if err := s.SetNote("abc", "  fixed the auth bug here\n"); err != nil {
+		t.Fatalf("SetNote failed: %v", err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.Notes["abc"]; got != "fixed the auth bug here" {
+		t.Errorf("Expected the trimmed note, got %q", got)
+	}
+
+	if err := reloaded.SetNote("abc", "   "); err != nil {
+		t.Fatalf("SetNote failed: %v", err)
+	}
+	if _, ok := reloaded.Notes["abc"]; ok {
+		t.Error("Expected a blank note to remove it")
+	}
+}
+
+func TestPickerSortPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if err := s.SetPickerSort("path"); err != nil {
+		t.Fatalf("SetPickerSort failed: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloaded.PickerSort != "path" {
+		t.Errorf("Expected picker sort path, got %q", reloaded.PickerSort)
+	}
+}
+
+// Opened times wait in memory for Save, which writes them with the rest
+func TestMarkOpenedSavesLater(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	when := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	s.MarkOpened("abc", when)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected no write on opening, got %v", err)
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if got := reloaded.Opened["abc"]; !got.Equal(when) {
+		t.Errorf("Expected opened at %v, got %v", when, got)
+	}
+	if _, ok := reloaded.Resumed["abc"]; ok {
+		t.Error("Expected opening to leave the resume history alone")
+	}
+}
+
+func TestPruneOpened(t *testing.T) {
+	s, _ := Load("")
+	s.MarkOpened("kept", time.Now())
+	s.MarkOpened("gone", time.Now())
+	s.PruneOpened(func(id string) bool { return id == "kept" })
+	if _, ok := s.Opened["gone"]; ok || len(s.Opened) != 1 {
+		t.Errorf("Expected only the existing session kept, got %v", s.Opened)
+	}
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// activeGlyph flags sessions Claude Code names as a project's last
+// session in its own config
+const activeGlyph = "★"
+
+// activeFirst moves the sessions Claude Code names as active to the top,
+// keeping the order within both groups. It returns a copy so the shared
+// session slice keeps its order.
+func activeFirst(sessions []model.SessionInfo, active map[string]bool) []model.SessionInfo {
+	if len(active) == 0 {
+		return sessions
+	}
+	ordered := make([]model.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		if active[session.ID] {
+			ordered = append(ordered, session)
+		}
+	}
+	for _, session := range sessions {
+		if !active[session.ID] {
+			ordered = append(ordered, session)
+		}
+	}
+	return ordered
+}
+
+// orderActiveFirst lists Claude Code's active sessions first when asked to
+func (m *Model) orderActiveFirst() {
+	if m.activeFirst {
+		m.filteredSessions = activeFirst(m.filteredSessions, m.activeSessions)
+	}
+}
+
+// toggleActiveFirst switches between listing Claude Code's active sessions
+// first and the plain sort order, staying on the selected session
+func (m *Model) toggleActiveFirst() tea.Cmd {
+	previousPath := m.selectedPath()
+	m.activeFirst = !m.activeFirst
+	m.refreshFiltered()
+
+	status := "Listing sessions in sort order"
+	if m.activeFirst {
+		status = "Listing active sessions first"
+	}
+	return m.reselect(previousPath, m.setStatus(status))
+}
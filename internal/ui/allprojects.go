@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// projectTagWidth is how many columns the project name takes at the start
+// of each row of the all-projects list
+const projectTagWidth = 12
+
+// toggleAllProjects switches the list between the browsed project and the
+// sessions of every project under the root, merged into one list
+func (m *Model) toggleAllProjects() tea.Cmd {
+	m.allProjects = !m.allProjects
+	m.repoOnly = false
+	if m.selected < len(m.filteredSessions) {
+		m.pendingSelectPath = m.filteredSessions[m.selected].FilePath
+	}
+	m.resetSearch()
+	m.scopeCache = make(map[SearchScope][]model.SessionInfo)
+	m.loading = true
+
+	status := "Showing this project's sessions"
+	if m.allProjects {
+		status = "Showing sessions from every project"
+	}
+	return tea.Batch(m.setStatus(status), m.loadSessions())
+}
+
+// spansProjects reports whether the list holds sessions of more than the
+// browsed project, so per-project actions must use the selected session's
+func (m *Model) spansProjects() bool {
+	return m.repoOnly || m.allProjects
+}
+
+// projectTag is the last element of the directory a session ran in, cut or
+// padded to projectTagWidth, naming its project in the all-projects list
+func projectTag(session model.SessionInfo) string {
+	project := session.Project
+	if project == "" {
+		project = filepath.Base(filepath.Dir(session.FilePath))
+	}
+	name := filepath.Base(model.DecodeProjectPath(project))
+	name = truncateRunes(name, projectTagWidth)
+	return name + strings.Repeat(" ", projectTagWidth-utf8.RuneCountInString(name))
+}
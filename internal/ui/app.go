@@ -4,19 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/davidpaquet/claude-session-browser/internal/clipboard"
+	"github.com/davidpaquet/claude-session-browser/internal/claudeconfig"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+	"github.com/davidpaquet/claude-session-browser/internal/gitrepo"
+	"github.com/davidpaquet/claude-session-browser/internal/history"
 	"github.com/davidpaquet/claude-session-browser/internal/model"
 	"github.com/davidpaquet/claude-session-browser/internal/parser"
 	"github.com/davidpaquet/claude-session-browser/internal/search"
+	"github.com/davidpaquet/claude-session-browser/internal/state"
 )
 
 // SearchState represents the current search mode
@@ -28,6 +41,40 @@ const (
 	SearchStateResults                    // User is navigating filtered results
 )
 
+// maxFilesShown is how many referenced files the details pane lists
+const maxFilesShown = 5
+
+// ListDisplay selects the label shown for each session in the list
+type ListDisplay int
+
+const (
+	ListDisplayID      ListDisplay = iota // Session UUID
+	ListDisplayTitle                      // First user prompt
+	ListDisplayPreview                    // Start of the last message
+	listDisplayCount
+)
+
+func (d ListDisplay) String() string {
+	switch d {
+	case ListDisplayTitle:
+		return "titles"
+	case ListDisplayPreview:
+		return "last-message previews"
+	}
+	return "session IDs"
+}
+
+// parseListDisplay maps a config value to a ListDisplay, defaulting to IDs
+func parseListDisplay(value string) ListDisplay {
+	switch value {
+	case "title":
+		return ListDisplayTitle
+	case "preview":
+		return ListDisplayPreview
+	}
+	return ListDisplayID
+}
+
 // Model is the app model
 type Model struct {
 	// Data
@@ -35,15 +82,48 @@ type Model struct {
 	fullSession   *model.FullSession
 	parser        *parser.Parser
 	clipboardMgr  *clipboard.Manager
-	claudeDir     string
+	claudeDir     string // project being browsed
+	startupDir    string // project chosen at startup
+	root          string // Claude projects root holding every project
 	version       string
+	config        *config.Config
 
 	// UI State
 	width         int
 	height        int
 	selected      int
 	scrollOffset  int
+	selectionGen  int // bumped on every selection move to debounce previews
+	listDisplay   ListDisplay
+	theme         Theme
+	sortKey       SortKey
+	thenSortKey   SortKey // breaks ties in sortKey
+	showSize      bool
+	showCount     bool
+	wrapList      bool // wrap long titles onto a second line instead of truncating
+	activeFirst   bool // list Claude Code's active sessions before the rest
+	copyFeedback  CopyFeedback
+	copiedPath    string // session copied last, badged in the list in badge mode
+	largeCopyPath string // session whose large transcript Y warned about
+	activeSessions map[string]bool // IDs Claude Code's config names as active
+	claudeConfig  string // Claude Code's user config, read for active sessions
+	costDisplay   CostDisplay
+	sessionsCost  bool // some listed session recorded a cost
+	shortIDs      bool
+	focusRefresh  bool // reload the list when the terminal regains focus
+	sidebarMinWidth int // show the stats sidebar above this width; 0 never
+	expandRaw     bool // show every kept raw message, not just the last
+	datePreset    DatePreset
+	detailsMode   DetailsMode // what the details pane shows
+	detailsDefault DetailsMode // the parsed view X returns to
+	showPosition  bool        // "Session 3 of 42" beside the details title
+	rawScroll     int // first line shown of the raw JSON view
+	listVersion   int // bumped when the listed sessions change
+	listCache     []string
+	listCacheKey  listCacheKey
 	loading       bool
+	refreshing    bool // a loadSessions is in flight
+	loadGen       int  // bumped per loadSessions so stale results are dropped
 	err           error
 
 	// Search State
@@ -51,36 +131,222 @@ type Model struct {
 	searchState      SearchState
 	searchInput      textinput.Model
 	searchQuery      string
+	filterMode       bool // the query fuzzy-filters names rather than searching content
+	resultsQuery     string // the query searchResults are for
+	searchEnter      SearchEnter
+	copyTop          bool // Enter asked to copy the top result, which is still loading
 	searchResults    []search.SearchResult
+	searchSessions   []model.SessionInfo // sessions the results index into
+	searchScope      SearchScope
+	searchBackend    search.Backend
+	searchIndex      *search.Index // session contents kept in memory for search; nil when off
+	scopeCache       map[SearchScope][]model.SessionInfo
 	filteredSessions []model.SessionInfo
+	ignoreCase       bool // seeded from config, toggled per run with ctrl+t
+	searchRecent     int  // search only this many latest sessions, 0 for all
+	searchHistory    *history.History
+	historyIndex     int // entry being recalled; len(entries) when not browsing
+	matchPreviewCount int // matches listed in the details pane, changed with +/-
+	matchScroll       int // first match listed in the details pane
+	hideEmpty        bool // hide sessions with no messages, toggled with e
+
+	// Transcript viewer
+	showTranscript       bool
+	transcript           []model.Message
+	transcriptScroll     int
+	transcriptFocus      int // index of the message holding the current match
+	transcriptAnchor     int // message to scroll to on next render, -1 for none
+	transcriptMatch      int // index into the current session's matches
+	transcriptCache      []string
+	transcriptStarts     []int
+	transcriptCacheWidth int
+	transcriptFolds      []transcriptFold // tool runs laid out by the last render
+	transcriptExpanded   map[int]bool     // tool runs unfolded, by first message
+
+	// Project picker
+	showPicker     bool
+	projects       []model.ProjectInfo
+	pickerInput    textinput.Model
+	pickerFilter   search.FilterEngine
+	pickerResults  []search.SearchResult
+	pickerSelected int
+	pickerScroll   int
+	pickerSort     ProjectSort // remembered in the state file
+	excludedProjects int // projects left out by projects.exclude, last counted
+	homeOnly       bool // picker and table skip projects outside $HOME
+
+	// Command palette
+	showPalette     bool
+	paletteInput    textinput.Model
+	paletteFilter   search.FilterEngine
+	paletteResults  []search.SearchResult
+	paletteSelected int
+	paletteScroll   int
+
+	// Note editor
+	showNote    bool
+	noteInput   textarea.Model
+	noteSession string // ID of the session whose note is being edited
+
+	// All-projects table
+	showTable         bool
+	showTree          bool // the date tree replaces the flat list
+	treeExpanded      map[string]bool // year, month and day nodes left open
+	treeSelected      int
+	treeScroll        int
+	tableLoading      bool
+	tableAll          []model.SessionInfo // every session, before the home filter
+	tableSessions     []model.SessionInfo
+	tableCost         bool // some session in the table recorded a cost
+	tableSort         TableColumn
+	tableSortDesc     bool
+	tableSelected     int
+	tableScroll       int
+	pendingSelectPath string // session to select once the list reloads
+	pendingSelectID   string // same, by ID, for sessions known before loading
+	pendingSearch     string // query to search once the list first loads
+	startInTable      bool   // open the all-projects table on start
+	jumpBuffer        string // session number typed so far in the list
+	jumpGen           int    // bumped per digit so only the last one times out
+	idleTimeout       time.Duration // quit after this long without a key; 0 never
+	lastInput         time.Time
+	// ctx is canceled when the app quits on its own, stopping searches
+	// still running in the background
+	ctx    context.Context
+	cancel context.CancelFunc
+	currentID         string // session the browser was launched from, if known
+	marked            map[string]model.SessionInfo // by file path, for combined export
+
+	// Activity remembered between runs
+	state         *state.State
+	openedChanged bool // state.Opened has times SaveState has not written
+	showResumed   bool // list only sessions resumed from the browser
+	repo        gitrepo.Repo
+	hasRepo     bool // started inside a git repository
+	repoOnly    bool // list every session run in repo instead of one project
+	allProjects bool // list the sessions of every project under the root
+
+	// Live refresh of the selected session while it is being written
+	liveTicking bool
+
+	// Pick mode: Enter returns the selected session to the caller
+	pickMode bool
+	picked   *model.SessionInfo
 
 	// Status
-	statusMsg     string
-	statusTimer   time.Time
+	statusMsg      string
+	statusTimer    time.Time
+	statusDuration time.Duration
 }
 
 // NewApp creates a new app
-func NewApp(claudeDir, version string) *Model {
+func NewApp(claudeDir, version string, cfg *config.Config) *Model {
 	// Initialize search input
 	searchInput := textinput.New()
 	searchInput.Placeholder = "Search sessions..."
 	searchInput.CharLimit = 100
 	searchInput.Width = 30
 
+	pickerInput := textinput.New()
+	pickerInput.Placeholder = "Type to filter projects..."
+	pickerInput.CharLimit = 100
+	pickerInput.Width = 40
+
+	paletteInput := textinput.New()
+	paletteInput.Placeholder = "Type a command..."
+	paletteInput.CharLimit = 100
+	paletteInput.Width = 40
+
+	setPaneBorder(parseBorder(cfg.Theme.Border))
+	setEllipsis(cfg.Theme.Ellipsis)
+
+	// main validates the flag; a bad config value falls back to auto
+	backend, err := search.ParseBackend(cfg.Search.Backend)
+	if err != nil {
+		log.Printf("config: %v", err)
+		backend = search.BackendAuto
+	}
+
+	// A broken state file only costs the remembered activity
+	st, err := state.Load(state.DefaultPath())
+	if err != nil {
+		log.Printf("state: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	
 	return &Model{
-		parser:       parser.NewParser(),
+		ctx:          ctx,
+		cancel:       cancel,
+		idleTimeout:  time.Duration(cfg.Idle.QuitAfterMinutes) * time.Minute,
+		lastInput:    time.Now(),
+		state:        st,
+		pickerSort:   parseProjectSort(st.PickerSort),
+		parser: parser.NewParserWithOptions(parser.Options{
+			Fields:             parser.Fields(cfg.Fields),
+			Summary:            parser.SummaryOptions(cfg.Summary),
+			RawMessages:        cfg.Details.RawMessages,
+			MergeContinuations: cfg.List.MergeContinuations,
+			Ellipsis:           cfg.Theme.Ellipsis,
+			ExcludeProjects:    cfg.Projects.Exclude,
+			TitleMessages:      cfg.List.TitleMessages,
+		}),
 		clipboardMgr: clipboard.NewManager(),
 		claudeDir:    claudeDir,
+		startupDir:   claudeDir,
+		root:         projectsRoot(claudeDir),
+		scopeCache:   make(map[SearchScope][]model.SessionInfo),
+		marked:       make(map[string]model.SessionInfo),
 		version:      version,
+		config:       cfg,
 		loading:      true,
 		width:        80,
 		height:       24,
 		searchInput:  searchInput,
+		pickerInput:  pickerInput,
+		pickerFilter: search.NewFilterEngine(),
+		paletteInput:  paletteInput,
+		noteInput:     newNoteInput(),
+		paletteFilter: search.NewFilterEngine(),
+		treeExpanded:  make(map[string]bool),
+		ignoreCase:   cfg.Search.IgnoreCase,
+		searchRecent: cfg.Search.Recent,
+		searchBackend: backend,
+		searchIndex:   newSearchIndex(cfg.Search.Index, backend),
+		searchEnter:   parseSearchEnter(cfg.Search.Enter),
+		matchPreviewCount: defaultMatchPreviewCount,
+		searchHistory: history.Load(history.DefaultPath(), cfg.Search.HistorySize),
+		listDisplay:  parseListDisplay(cfg.List.Display),
+		theme:        newTheme(cfg.Theme),
+		sortKey:      parseSortKey(cfg.List.Sort),
+		thenSortKey:  parseSortKey(cfg.List.ThenSort),
+		hideEmpty:    cfg.List.HideEmpty,
+		showSize:     cfg.List.ShowSize,
+		showCount:    cfg.List.ShowCount,
+		wrapList:     cfg.List.Wrap,
+		allProjects:  cfg.List.AllProjects,
+		activeFirst:  cfg.List.ActiveFirst,
+		copyFeedback: parseCopyFeedback(cfg.Copy.Feedback),
+		detailsMode:  parseDetailsMode(cfg.Details.View),
+		detailsDefault: parseDetailsMode(cfg.Details.View),
+		showPosition: cfg.Details.ShowPosition,
+		claudeConfig: claudeconfig.DefaultPath(),
+		costDisplay:  parseCostDisplay(cfg.Cost.Display),
+		shortIDs:     cfg.List.ShortIDs,
+		focusRefresh: cfg.List.RefreshOnFocus,
+		sidebarMinWidth: cfg.Layout.SidebarMinWidth,
+		homeOnly:     cfg.Projects.HomeOnly,
+		tableSort:     TableColumnLastActive,
+		tableSortDesc: true,
 	}
 }
 
 func (m *Model) Init() tea.Cmd {
-	return m.loadSessions()
+	cmds := []tea.Cmd{m.loadSessions(), scheduleClockTick(), m.warnMissingClaude(), m.scheduleIdleCheck()}
+	if m.startInTable {
+		cmds = append(cmds, m.openTable())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -90,97 +356,298 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		return m, nil
 		
+	case tea.FocusMsg:
+		return m, m.refreshOnFocus()
+		
 	case sessionsLoadedMsg:
+		// A newer load (e.g. after switching project) supersedes this one
+		if msg.gen != m.loadGen {
+			return m, nil
+		}
+		m.refreshing = false
 		m.loading = false
 		m.sessions = msg.sessions
+		m.activeSessions = msg.active
 		m.err = msg.err
 		
-		// Sort by most recent
-		sort.Slice(m.sessions, func(i, j int) bool {
-			return m.sessions[i].LastActive.After(m.sessions[j].LastActive)
+		// Unreadable files only cost their own entries
+		var warnCmd tea.Cmd
+		var skipped *parser.SkippedError
+		if errors.As(msg.err, &skipped) {
+			m.err = nil
+			warnCmd = m.setStatusFor(skipped.Error(), warningStatusDuration)
+		}
+		
+		m.sessionsCost = anyCost(m.sessions)
+		
+		// Sort by most recent, by ID among equals so refreshes keep the
+		// same order
+		sort.SliceStable(m.sessions, func(i, j int) bool {
+			return sessionLess(m.sessions[i], m.sessions[j], SortRecent, SortRecent, nil)
 		})
 		
-		// Initialize search engine with sessions
+		// Initialize search engine with sessions; an empty project must not
+		// keep searching the previous one's
+		m.searchEngine = nil
 		if len(m.sessions) > 0 {
-			m.searchEngine = search.NewEngine(m.sessions)
-			m.filteredSessions = m.sessions // Initially show all sessions
+			m.searchEngine = search.NewEngineWithIndex(m.sessions, m.searchBackend, m.searchIndex)
 		}
+		m.refreshFiltered() // Initially show all sessions
+		warnCmd = tea.Batch(warnCmd, m.warmIndex(m.sessions))
 		
 		// Select first and load it
 		if len(m.filteredSessions) > 0 {
 			m.selected = 0
 			m.scrollOffset = 0 // Reset scroll
-			return m, m.loadFullSession(m.filteredSessions[0].FilePath)
+			
+			// Land on a session picked elsewhere (e.g. the table view)
+			if m.pendingSelectPath != "" || m.pendingSelectID != "" {
+				for i, session := range m.filteredSessions {
+					if session.FilePath == m.pendingSelectPath || session.ID == m.pendingSelectID {
+						m.selected = i
+						break
+					}
+				}
+				m.pendingSelectPath = ""
+				m.pendingSelectID = ""
+				m.ensureVisible()
+			}
+			return m, tea.Batch(warnCmd, m.startPendingSearch(), m.loadFullSession(m.filteredSessions[m.selected].FilePath))
 		}
-		return m, nil
+		return m, tea.Batch(warnCmd, m.startPendingSearch())
 		
 	case fullSessionLoadedMsg:
+		// A load that finished after the selection moved on is stale,
+		// whether it worked or not
+		if msg.filePath != m.selectedPath() {
+			return m, nil
+		}
+		// Live reloads of the same session keep the match list where it was
+		opened := m.fullSession == nil || m.fullSession.FilePath != msg.filePath
+		if opened {
+			m.matchScroll = 0
+			m.rawScroll = 0
+		}
 		m.fullSession = msg.session
 		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Error: %v", msg.err)
-			m.statusTimer = time.Now()
+			status := fmt.Sprintf("Error: %v", msg.err)
+			// A copy waiting on this load would otherwise go off on a later one
+			if m.copyTop {
+				m.copyTop = false
+				status += " (nothing copied)"
+			}
+			return m, m.setStatus(status)
+		}
+		m.updateMessageCount(msg.session)
+		if opened && msg.session != nil {
+			m.markOpened(msg.session.ID)
+		}
+		return m, tea.Batch(m.scheduleLiveRefresh(), m.copyTopLoaded())
+		
+	case previewTickMsg:
+		// Only load if the selection has not moved since the tick was scheduled
+		if msg.gen == m.selectionGen {
+			return m, m.loadFullSession(msg.filePath)
+		}
+		return m, nil
+		
+	case indexWarmedMsg:
+		return m, m.handleIndexWarmed(msg)
+		
+	case clockTickMsg:
+		// Only the cached rows need redoing; the data is unchanged
+		m.invalidateList()
+		return m, scheduleClockTick()
+		
+	case liveTickMsg:
+		return m, m.handleLiveTick(msg)
+		
+	case rescanMsg:
+		return m, m.handleRescan(msg)
+		
+	case idleTickMsg:
+		return m, m.handleIdleTick()
+		
+	case jumpTimeoutMsg:
+		if msg.gen == m.jumpGen {
+			m.jumpBuffer = ""
+		}
+		return m, nil
+		
+	case tableLoadedMsg:
+		return m, m.handleTableLoaded(msg)
+		
+	case shareCompleteMsg:
+		return m, m.handleShareComplete(msg)
+		
+	case combinedExportMsg:
+		return m, m.handleCombinedExport(msg)
+		
+	case pipeReadyMsg:
+		return m, m.handlePipeReady(msg)
+		
+	case transcriptReadyMsg:
+		return m, m.handleTranscriptReady(msg)
+		
+	case pipeDoneMsg:
+		return m, m.handlePipeDone(msg)
+		
+	case projectsLoadedMsg:
+		if msg.err != nil {
+			m.closePicker()
+			return m, m.setStatus(fmt.Sprintf("Error: %v", msg.err))
+		}
+		m.projects = msg.projects
+		m.excludedProjects = msg.excluded
+		sortProjects(m.projects, m.pickerSort)
+		m.filterProjects()
+		// Start on the project currently being browsed
+		for i, result := range m.pickerResults {
+			if m.projects[result.SessionIndex].Path == m.claudeDir {
+				m.pickerSelected = i
+				break
+			}
 		}
 		return m, nil
 		
 	case clearStatusMsg:
-		m.statusMsg = ""
+		// A newer message may have replaced the one this tick was for
+		if msg.timer.Equal(m.statusTimer) {
+			m.statusMsg = ""
+		}
+		return m, nil
+		
+	case transcriptLoadedMsg:
+		if msg.err != nil {
+			return m, m.setStatus(fmt.Sprintf("Error: %v", msg.err))
+		}
+		m.transcript = msg.messages
+		m.transcriptCache = nil
+		m.transcriptExpanded = make(map[int]bool)
+		m.transcriptScroll = 0
+		m.transcriptFocus = -1
+		m.transcriptAnchor = -1
+		if msg.focusLine > 0 {
+			m.focusTranscriptLine(msg.focusLine)
+		}
+		m.showTranscript = true
+		if m.fullSession != nil {
+			return m, m.markViewed(m.fullSession.ID, len(msg.messages))
+		}
 		return m, nil
 		
 	case searchCompleteMsg:
-		// Ignore if search query has changed
-		if msg.query != m.searchQuery {
+		// Ignore if search query, options or scope have changed
+		if msg.query != m.searchQuery || msg.filter != m.filterMode || msg.opts != m.searchOptions() || msg.scope != m.effectiveScope() {
 			return m, nil
 		}
 		
 		if msg.err != nil {
-			m.statusMsg = fmt.Sprintf("Search error: %v", msg.err)
-			m.statusTimer = time.Now()
-			return m, nil
+			return m, m.setStatus(fmt.Sprintf("Search error: %v", msg.err))
+		}
+		if msg.listed {
+			m.scopeCache[msg.scope] = msg.sessions
 		}
 		
+		// Remember the selected session so refining the query keeps our place
+		previousPath := m.selectedPath()
+		
 		// Store search results
 		m.searchResults = msg.results
+		m.resultsQuery = msg.query
+		m.matchScroll = 0
+		m.searchSessions = msg.sessions
 		
 		// Update filtered sessions
-		m.filteredSessions = make([]model.SessionInfo, 0, len(msg.results))
-		for _, result := range msg.results {
-			if result.SessionIndex < len(m.sessions) {
-				m.filteredSessions = append(m.filteredSessions, m.sessions[result.SessionIndex])
-			}
-		}
+		m.refreshFiltered()
 		
 		// Update status
+		var statusCmd tea.Cmd
 		if len(m.filteredSessions) == 0 {
-			m.statusMsg = fmt.Sprintf("No matches found for '%s'", m.searchQuery)
+			statusCmd = m.setStatus(fmt.Sprintf("No matches found for '%s'%s", m.searchQuery, m.searchScopeNote()))
 		} else {
-			m.statusMsg = fmt.Sprintf("Found %d sessions matching '%s'", len(m.filteredSessions), m.searchQuery)
+			statusCmd = m.setStatus(fmt.Sprintf("Found %d sessions matching '%s'%s", len(m.filteredSessions), m.searchQuery, m.searchScopeNote()))
 		}
-		m.statusTimer = time.Now()
 		
-		// Reset selection and load first session if available
-		if len(m.filteredSessions) > 0 {
-			m.selected = 0
-			m.scrollOffset = 0
-			return m, m.loadFullSession(m.filteredSessions[0].FilePath)
+		// Stay on the previous session if it still matches, else go to the top
+		cmd := m.reselect(previousPath, statusCmd)
+		if m.copyTop {
+			cmd = tea.Batch(cmd, m.copyTopResult())
 		}
-		
-		return m, nil
+		return m, cmd
 		
 	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		
+		// The transcript viewer takes over the keyboard while open
+		if m.showTranscript {
+			return m.updateTranscript(msg)
+		}
+		if m.showPicker {
+			return m.updatePicker(msg)
+		}
+		if m.showPalette {
+			return m.updatePalette(msg)
+		}
+		if m.showNote {
+			return m.updateNote(msg)
+		}
+		if m.showTable {
+			return m.updateTable(msg)
+		}
+		if m.showTree {
+			return m.updateTree(msg)
+		}
+		
 		// Handle based on current search state
 		switch m.searchState {
 		case SearchStateInput:
+			// Case, recency, scope and history only apply to content search
+			if m.filterMode {
+				switch msg.String() {
+				case "ctrl+t", "ctrl+r", "ctrl+s", "up", "down":
+					return m, nil
+				}
+			}
+			
 			// In search input mode
 			switch msg.String() {
 			case "esc":
 				// Cancel search entirely
-				m.clearSearch()
-				return m, nil
+				return m, m.clearSearch()
+			case "ctrl+t":
+				return m, m.toggleIgnoreCase()
+			case "ctrl+r":
+				return m, m.toggleSearchRecent()
+			case "ctrl+s":
+				return m, m.cycleSearchScope()
 			case "tab", "enter":
 				// Exit input mode, enter results mode
+				var cmd tea.Cmd
 				if m.searchQuery != "" {
 					m.searchState = SearchStateResults
 					m.searchInput.Blur()
+					if !m.pickMode && !m.filterMode {
+						if err := m.searchHistory.Add(m.searchQuery); err != nil {
+							cmd = m.setStatus(fmt.Sprintf("Could not save search history: %v", err))
+						}
+					}
+					// Tab only ever moves to the results
+					if msg.String() == "enter" && m.searchEnter == SearchEnterCopy && !m.pickMode {
+						m.copyTop = true
+						cmd = tea.Batch(cmd, m.copyTopResult())
+					}
+				}
+				return m, cmd
+			case "up", "down":
+				// Shell-style recall, only when it cannot be cursor movement
+				browsing := m.historyIndex < len(m.searchHistory.Entries())
+				if browsing || m.searchInput.Value() == "" || m.searchInput.Position() == 0 {
+					step := -1
+					if msg.String() == "down" {
+						step = 1
+					}
+					return m, m.recallHistory(step)
 				}
 				return m, nil
 			default:
@@ -188,117 +655,77 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				var cmd tea.Cmd
 				m.searchInput, cmd = m.searchInput.Update(msg)
 				m.searchQuery = m.searchInput.Value()
+				m.historyIndex = len(m.searchHistory.Entries())
 				
 				// Trigger async search
 				if m.searchQuery != "" {
-					m.statusMsg = "Searching..."
-					m.statusTimer = time.Now()
-					return m, tea.Batch(cmd, m.performSearchCmd())
+					return m, tea.Batch(cmd, m.setStatus("Searching..."), m.performSearchCmd())
 				} else {
 					// Clear search immediately if query is empty
-					m.filteredSessions = m.sessions
 					m.searchResults = nil
+					m.resultsQuery = ""
+					m.refreshFiltered()
 					m.statusMsg = ""
 				}
-				return m, cmd
+				return m, tea.Batch(cmd, m.syncSelection())
 			}
 			
 		case SearchStateResults:
 			// In search results mode - handle navigation
 			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
 			case "esc":
 				// Clear search and return to normal
-				m.clearSearch()
-				return m, nil
+				return m, m.clearSearch()
 			case "/":
+				if m.filterMode {
+					return m, m.enterSearchMode()
+				}
 				// Return to search input mode
 				m.searchState = SearchStateInput
 				m.searchInput.Focus()
+				m.historyIndex = len(m.searchHistory.Entries())
 				return m, textinput.Blink
-			case "up", "k":
-				if m.selected > 0 {
-					m.selected--
-					m.ensureVisible()
-					if m.selected < len(m.filteredSessions) {
-						return m, m.loadFullSession(m.filteredSessions[m.selected].FilePath)
-					}
-				}
-			case "down", "j":
-				if m.selected < len(m.filteredSessions)-1 {
-					m.selected++
-					m.ensureVisible()
-					if m.selected < len(m.filteredSessions) {
-						return m, m.loadFullSession(m.filteredSessions[m.selected].FilePath)
-					}
-				}
-			case "enter":
-				if m.fullSession != nil {
-					cmd := m.fullSession.GetResumeCommand()
-					if err := m.clipboardMgr.Copy(cmd); err != nil {
-						m.statusMsg = fmt.Sprintf("Copy failed: %v", err)
-					} else {
-						m.statusMsg = "Copied to clipboard!"
-					}
-					m.statusTimer = time.Now()
-					return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-						return clearStatusMsg{}
-					})
-				}
-			case "r":
-				m.loading = true
-				m.clearSearch()
-				return m, m.loadSessions()
+			case "+", "=":
+				return m, m.resizeMatchPreview(1)
+			case "-":
+				return m, m.resizeMatchPreview(-1)
+			case "]":
+				m.scrollMatches(1)
+				return m, nil
+			case "[":
+				m.scrollMatches(-1)
+				return m, nil
+			case "g", "home":
+				m.showMatch(0)
+				return m, nil
+			case "G", "end":
+				m.showMatch(len(m.currentMatches()) - 1)
+				return m, nil
+			case "ctrl+t":
+				return m, m.toggleIgnoreCase()
+			case "ctrl+r":
+				return m, m.toggleSearchRecent()
+			case "ctrl+s":
+				return m, m.cycleSearchScope()
+			}
+			if cmd, ok := m.handleListKey(msg); ok {
+				return m, cmd
 			}
 			
 		default:
 			// Normal mode - no search active
+			if cmd, ok := m.handleJumpKey(msg); ok {
+				return m, cmd
+			}
+			if cmd, ok := m.handleListKey(msg); ok {
+				return m, cmd
+			}
 			switch msg.String() {
-			case "ctrl+c", "q":
-				return m, tea.Quit
-				
 			case "/":
-				m.enterSearchMode()
-				return m, textinput.Blink
-				
-			case "up", "k":
-				if m.selected > 0 {
-					m.selected--
-					m.ensureVisible()
-					if m.selected < len(m.filteredSessions) {
-						return m, m.loadFullSession(m.filteredSessions[m.selected].FilePath)
-					}
-				}
-				
-			case "down", "j":
-				if m.selected < len(m.filteredSessions)-1 {
-					m.selected++
-					m.ensureVisible()
-					if m.selected < len(m.filteredSessions) {
-						return m, m.loadFullSession(m.filteredSessions[m.selected].FilePath)
-					}
-				}
-				
-			case "enter":
-				if m.fullSession != nil {
-					cmd := m.fullSession.GetResumeCommand()
-					if err := m.clipboardMgr.Copy(cmd); err != nil {
-						m.statusMsg = fmt.Sprintf("Copy failed: %v", err)
-					} else {
-						m.statusMsg = "Copied to clipboard!"
-					}
-					m.statusTimer = time.Now()
-					// Clear the message after 2 seconds
-					return m, tea.Tick(2*time.Second, func(time.Time) tea.Msg {
-						return clearStatusMsg{}
-					})
-				}
+				return m, m.enterSearchMode()
 				
-			case "r":
-				m.loading = true
-				m.clearSearch()
-				return m, m.loadSessions()
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
+				return m, m.typeJumpDigit(msg.String())
 			}
 		}
 	}
@@ -306,6 +733,98 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleListKey runs the keys the session list takes both with and without
+// search results shown. It reports false for any other key, which is left
+// to the mode's own bindings.
+func (m *Model) handleListKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return tea.Quit, true
+	case "f":
+		return m.enterFilterMode(), true
+	case "v":
+		// Opens at the first match when searching
+		return m.openTranscript(), true
+	case "p":
+		return m.openPicker(), true
+	case "ctrl+g":
+		return m.toggleRepoOnly(), true
+	case "A":
+		return m.toggleAllProjects(), true
+	case ":", "ctrl+p":
+		return m.openPalette(), true
+	case "t":
+		return m.cycleListDisplay(), true
+	case "o":
+		return m.cycleSort(), true
+	case "e":
+		return m.toggleHideEmpty(), true
+	case "d":
+		return m.cycleDatePreset(), true
+	case "s":
+		return m.toggleShowSize(), true
+	case "R":
+		return m.toggleResumed(), true
+	case "c":
+		return m.copyProjectPath(), true
+	case "J":
+		return m.copyLastMessageJSON(), true
+	case "N":
+		return m.openNoteEditor(), true
+	case "M":
+		return m.copyMarkdownSummary(), true
+	case "L":
+		return m.copySessionLink(), true
+	case "C":
+		return m.copyCompactResume(), true
+	case "U":
+		return m.rescanSelected(), true
+	case "x":
+		return m.toggleExpandRaw(), true
+	case "X":
+		return m.toggleRawView(), true
+	case "pgup":
+		m.scrollRaw(-1)
+		return nil, true
+	case "pgdown":
+		m.scrollRaw(1)
+		return nil, true
+	case "w":
+		return m.shareSession(), true
+	case " ":
+		return m.toggleMark(), true
+	case "W":
+		return m.exportMarked(), true
+	case "|":
+		return m.pipeSession(), true
+	case "Y":
+		return m.copyTranscript(), true
+	case "T":
+		return m.openTable(), true
+	case "D":
+		return m.toggleTree(), true
+	case "up", "k":
+		if m.selected > 0 {
+			m.selected--
+			m.ensureVisible()
+			return m.previewSelected(), true
+		}
+		return nil, true
+	case "down", "j":
+		if m.selected < len(m.filteredSessions)-1 {
+			m.selected++
+			m.ensureVisible()
+			return m.previewSelected(), true
+		}
+		return nil, true
+	case "enter":
+		return m.confirmSelected(), true
+	case "r":
+		return m.refresh(), true
+	}
+	return nil, false
+}
+
 func (m *Model) View() string {
 	if m.loading {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
@@ -317,28 +836,68 @@ func (m *Model) View() string {
 			errorStyle.Render(fmt.Sprintf("Error: %v\n\nPress q to quit", m.err)))
 	}
 	
-	// Calculate pane dimensions
-	// Reserve space for status bar and search bar if active
-	reservedHeight := 1 // status bar
-	if m.searchState != SearchStateNormal {
-		reservedHeight += 3 // search bar with border
+	if m.tooSmall() {
+		return m.renderTooSmall()
 	}
-	availableHeight := m.height - reservedHeight
 	
-	// Fixed width for left pane (including margin)
-	leftWidth := 40
-	if m.width < 80 {
-		leftWidth = m.width / 2
+	if m.showTranscript {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.renderTranscript(m.width, m.height-1),
+			m.renderStatusBar(),
+		)
 	}
-	// Right pane gets remaining width minus the left margin
-	rightWidth := m.width - leftWidth - 1
+	
+	if m.showPicker {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.renderPicker(m.width, m.height-1),
+			m.renderStatusBar(),
+		)
+	}
+	
+	if m.showPalette {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.renderPalette(m.width, m.height-1),
+			m.renderStatusBar(),
+		)
+	}
+	
+	if m.showNote {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.renderNote(m.width, m.height-1),
+			m.renderStatusBar(),
+		)
+	}
+	
+	if m.showTable {
+		return lipgloss.JoinVertical(
+			lipgloss.Left,
+			m.renderTable(m.width, m.height-1),
+			m.renderStatusBar(),
+		)
+	}
+	
+	// Calculate pane dimensions
+	// Fixed width for left pane (including margin); the details pane gets
+	// the rest
+	leftWidth := m.listPaneWidth()
+	rightWidth, availableHeight := m.detailsSize()
 	
 	// Render panes with consistent height
 	leftPane := m.renderSessionList(leftWidth, availableHeight)
+	if m.showTree {
+		leftPane = m.renderTree(leftWidth, availableHeight)
+	}
 	rightPane := m.renderDetails(rightWidth, availableHeight)
 	
 	// Join horizontally with no gap
 	main := lipgloss.JoinHorizontal(lipgloss.Top, leftPane, rightPane)
+	if m.showSidebar() {
+		main = lipgloss.JoinHorizontal(lipgloss.Top, main, m.renderSidebar(sidebarWidth, availableHeight))
+	}
 	
 	// Add search bar if in search mode
 	components := []string{main}
@@ -366,23 +925,31 @@ func (m *Model) renderSessionList(width, height int) string {
 	// Build content
 	lines := []string{}
 	title := "Sessions"
+	if m.repoOnly {
+		title = "Sessions in " + filepath.Base(m.repo.Root)
+	} else if m.allProjects {
+		title = "Sessions in all projects"
+	}
+	if m.showResumed {
+		title = "Recently Resumed"
+	}
+	if m.datePreset != DateAll {
+		title += " · " + m.datePreset.String()
+	}
 	if m.searchState != SearchStateNormal {
-		title = fmt.Sprintf("Sessions (%d matches)", len(m.filteredSessions))
+		title = fmt.Sprintf("%s (%d matches)", title, len(m.filteredSessions))
 	}
 	lines = append(lines, titleStyle.Render(title))
 	lines = append(lines, "")
 	
-	// Calculate how many items we can show (minus title and blank line)
+	// Calculate how many lines the items can use (minus title and blank line)
 	itemsHeight := innerHeight - 2
 	if itemsHeight < 1 {
 		itemsHeight = 1
 	}
 	
 	// Ensure scroll offset is valid
-	maxScroll := len(m.filteredSessions) - itemsHeight
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
+	maxScroll := m.maxListScroll(itemsHeight, innerWidth)
 	if m.scrollOffset > maxScroll {
 		m.scrollOffset = maxScroll
 	}
@@ -390,64 +957,260 @@ func (m *Model) renderSessionList(width, height int) string {
 		m.scrollOffset = 0
 	}
 	
-	// Render visible sessions
+	// Render the sessions that fit from the scroll offset down
 	visibleStart := m.scrollOffset
-	visibleEnd := m.scrollOffset + itemsHeight
-	if visibleEnd > len(m.filteredSessions) {
-		visibleEnd = len(m.filteredSessions)
+	visibleEnd := m.listVisibleEnd(visibleStart, itemsHeight, innerWidth)
+	
+	lines = append(lines, m.listItemLines(visibleStart, visibleEnd, innerWidth)...)
+	
+	// Pad to fill the inner height
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
 	}
 	
-	for i := visibleStart; i < visibleEnd; i++ {
+	// Join lines and apply container style
+	content := strings.Join(lines, "\n")
+	return sessionListStyle.
+		Width(width).
+		Height(height).
+		Render(content)
+}
+
+// listCacheKey captures everything the rendered list rows depend on
+type listCacheKey struct {
+	version    int
+	start, end int
+	selected   int
+	width      int
+	display    ListDisplay
+	showSize   bool
+	showCount  bool
+	shortIDs   bool
+	wrap       bool
+	projects   bool
+	query      string
+	clock      int64 // relative times and the live marker age with the clock
+}
+
+// invalidateList forces the list rows to be re-rendered on the next frame.
+// Call it whenever the sessions shown, or their fields, change.
+func (m *Model) invalidateList() {
+	m.listVersion++
+}
+
+// listItemLines renders the rows from start to end, reusing the previous
+// frame's rows when nothing they depend on has changed
+func (m *Model) listItemLines(start, end, innerWidth int) []string {
+	key := listCacheKey{
+		version:   m.listVersion,
+		start:     start,
+		end:       end,
+		selected:  m.selected,
+		width:     innerWidth,
+		display:   m.listDisplay,
+		showSize:  m.showSize,
+		showCount: m.showCount,
+		shortIDs:  m.shortIDs,
+		wrap:      m.wrapList,
+		projects:  m.allProjects,
+		query:     m.searchQuery,
+		clock:     time.Now().Unix() / 10,
+	}
+	if m.listCache != nil && m.listCacheKey == key {
+		return m.listCache
+	}
+
+	// Match counts are colored relative to the session with the most
+	counts := make(map[string]int)
+	most := 0
+	if m.searchQuery != "" && !m.filterMode {
+		for _, result := range m.searchResults {
+			counts[result.FilePath] = len(result.Matches)
+			most = max(most, len(result.Matches))
+		}
+	}
+
+	items := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
 		session := m.filteredSessions[i]
+		timeStr, matchIndicator, unread := m.rowDetails(session)
 		
-		// Format relative time
-		timeStr := getRelativeTime(session.LastActive)
-		
-		// Truncate ID
-		id := session.ID
-		if len(id) > 24 {
-			id = "..." + id[len(id)-21:]
+		// Format line to fit within inner width
+		var line, continuation string
+		hasContinuation := false
+		rowWidth := innerWidth
+		tag := ""
+		if m.allProjects {
+			tag = projectTag(session) + " "
+			rowWidth -= projectTagWidth + 1
 		}
-		
-		// Add match indicator if searching
-		matchIndicator := ""
-		if m.searchQuery != "" {
-			// Find match count for this session
-			for _, result := range m.searchResults {
-				if result.SessionID == session.ID {
-					matchIndicator = fmt.Sprintf(" [%d]", len(result.Matches))
-					break
+		label := m.sessionLabel(session)
+		if label == "" {
+			// Truncate ID, leaving room for the project tag and the size
+			// and count columns when shown
+			idWidth := 24 - (innerWidth - rowWidth)
+			if m.showSize {
+				idWidth -= 10
+			}
+			if m.showCount {
+				idWidth -= 11
+			}
+			if idWidth < 8 {
+				idWidth = 8
+			}
+			id := session.ID
+			if m.shortIDs {
+				id = session.ShortID()
+			} else if len(id) > idWidth {
+				id = ellipsis + id[len(id)-idWidth+ellipsisWidth():]
+			}
+			if pad := idWidth - utf8.RuneCountInString(id); pad > 0 {
+				id += strings.Repeat(" ", pad)
+			}
+			line = fmt.Sprintf("%s%s %s", id, matchIndicator, timeStr)
+		} else {
+			// Titles and previews take whatever room the suffix leaves,
+			// going on to a second line when wrapping
+			suffix := matchIndicator + " " + timeStr
+			labelWidth := rowLabelWidth(suffix, rowWidth)
+			if m.wrapList && utf8.RuneCountInString(label) > labelWidth {
+				label, continuation = splitLabel(label, labelWidth)
+				hasContinuation = true
+			}
+			label = truncateRunes(label, labelWidth)
+			padding := labelWidth - utf8.RuneCountInString(label)
+			line = label + strings.Repeat(" ", padding) + suffix
+		}
+		line = tag + line
+		if len(m.marked) > 0 {
+			mark := "  "
+			if _, ok := m.marked[session.FilePath]; ok {
+				mark = "✓ "
+			}
+			line = mark + line
+		}
+		rowLines := []string{truncateRunes(line, innerWidth)}
+		if count, ok := counts[session.FilePath]; ok && i != m.selected {
+			// Color after truncating so widths count plain text; the
+			// selected row keeps its own colors
+			if style, ok := m.theme.matchCountStyle(count, most); ok {
+				if at := strings.LastIndex(rowLines[0], matchIndicator); at >= 0 {
+					rowLines[0] = rowLines[0][:at] + style.Render(matchIndicator) + rowLines[0][at+len(matchIndicator):]
 				}
 			}
 		}
-		
-		// Format line to fit within inner width
-		line := fmt.Sprintf("%-24s%s %s", id, matchIndicator, timeStr)
-		if len(line) > innerWidth {
-			line = line[:innerWidth]
+		if hasContinuation {
+			// Indented past the mark column so it reads as part of the label
+			indent := "  "
+			if len(m.marked) > 0 {
+				indent += "  "
+			}
+			rowLines = append(rowLines, truncateRunes(indent+continuation, innerWidth))
 		}
 		
 		// Apply selection style
-		if i == m.selected {
-			line = selectedItemStyle.Render(line)
-		} else {
-			line = sessionItemStyle.Render(line)
+		for _, line := range rowLines {
+			if i == m.selected {
+				line = selectedItemStyle.Render(line)
+			} else if unread > 0 {
+				line = unreadItemStyle.Render(line)
+			} else {
+				line = sessionItemStyle.Render(line)
+			}
+			items = append(items, line)
 		}
-		
-		lines = append(lines, line)
 	}
 	
-	// Pad to fill the inner height
-	for len(lines) < innerHeight {
+	m.listCache = items
+	m.listCacheKey = key
+	return items
+}
+
+// detailsTitle is the details pane's title with the selected session's place
+// in the list at the right, left off when the pane is too narrow for both
+func (m *Model) detailsTitle(width int) string {
+	title := titleStyle.Render("Session Details")
+	if !m.showPosition || m.selected >= len(m.filteredSessions) {
+		return title
+	}
+	position := fmt.Sprintf("Session %d of %d", m.selected+1, len(m.filteredSessions))
+	gap := width - lipgloss.Width(title) - lipgloss.Width(position)
+	if gap < 2 {
+		return title
+	}
+	return title + strings.Repeat(" ", gap) + mutedTextStyle.Render(position)
+}
+
+// detailsInfoLines are the lines the details pane shows above the search
+// matches: the session's info, note, summary, commands and files
+func (m *Model) detailsInfoLines(innerWidth int) []string {
+	var lines []string
+	lines = append(lines, m.detailsTitle(innerWidth))
+	lines = append(lines, "")
+	
+	// Basic info
+	lines = append(lines, fmt.Sprintf("ID: %s", m.fullSession.ID))
+	lines = append(lines, fmt.Sprintf("Messages: %d", m.fullSession.MessageCount))
+	if m.fullSession.ImageCount > 0 {
+		lines = append(lines, fmt.Sprintf("Images: %d", m.fullSession.ImageCount))
+	}
+	if m.costDisplay.showsCost(m.sessionsCost) {
+		lines = append(lines, fmt.Sprintf("Cost: $%.4f", m.fullSession.TotalCostUSD))
+	}
+	if m.fullSession.Model != "" {
+		lines = append(lines, fmt.Sprintf("Model: %s", m.fullSession.Model))
+	}
+	if tokens := m.fullSession.Tokens; tokens.Total() > 0 && !m.showSidebar() {
+		lines = append(lines, fmt.Sprintf("Tokens: %d in, %d out, %d cached",
+			tokens.Input, tokens.Output, tokens.CacheCreation+tokens.CacheRead))
+		if ratio, ok := tokens.CacheHitRatio(); ok {
+			lines = append(lines, fmt.Sprintf("Cache hits: %.0f%% of input", ratio*100))
+		}
+	}
+	lines = append(lines, "")
+	
+	// The user's own note comes before anything derived from the session
+	lines = append(lines, m.noteLines(innerWidth-2)...)
+	
+	// Summary
+	if m.fullSession.Summary != "" {
+		lines = append(lines, "Summary:")
+		// Line breaks are only left in with summary.keepNewlines
+		for _, paragraph := range strings.Split(m.fullSession.Summary, "\n") {
+			for _, line := range wrapText(paragraph, innerWidth-2) {
+				lines = append(lines, "  "+line)
+			}
+		}
 		lines = append(lines, "")
 	}
 	
-	// Join lines and apply container style
-	content := strings.Join(lines, "\n")
-	return sessionListStyle.
-		Width(width).
-		Height(height).
-		Render(content)
+	// How the session was managed, e.g. whether it was compacted
+	if commands := m.fullSession.SlashCommands; len(commands) > 0 {
+		lines = append(lines, "Slash Commands:")
+		for _, command := range commands {
+			line := "  " + command.Name
+			if command.Count > 1 {
+				line += fmt.Sprintf(" ×%d", command.Count)
+			}
+			lines = append(lines, truncateRunes(line, innerWidth))
+		}
+		lines = append(lines, "")
+	}
+	
+	// Files the session read or edited, listed in full by the sidebar
+	// when it is shown
+	if files := m.fullSession.ReferencedFiles; len(files) > 0 && !m.showSidebar() {
+		lines = append(lines, filesHeading(m.fullSession))
+		for i, file := range files {
+			if i >= maxFilesShown {
+				lines = append(lines, mutedTextStyle.Render(fmt.Sprintf("  +%d more", len(files)-maxFilesShown)))
+				break
+			}
+			lines = append(lines, "  "+truncateRunes(model.ShortenHome(file), innerWidth-2))
+		}
+		lines = append(lines, "")
+	}
+	return lines
 }
 
 func (m *Model) renderDetails(width, height int) string {
@@ -458,6 +1221,12 @@ func (m *Model) renderDetails(width, height int) string {
 	if innerHeight < 1 || innerWidth < 1 {
 		return detailsStyle.Width(width).Height(height).Render("")
 	}
+	if m.showNoMatches() {
+		return m.renderNoMatches(width, height)
+	}
+	if m.detailsMode == DetailsRaw && m.fullSession != nil {
+		return m.renderRawView(width, height)
+	}
 	
 	lines := []string{}
 	
@@ -471,46 +1240,34 @@ func (m *Model) renderDetails(width, height int) string {
 		return detailsStyle.Width(width).Height(height).Render(content)
 	}
 	
-	// Build content
-	lines = append(lines, titleStyle.Render("Session Details"))
-	lines = append(lines, "")
-	
-	// Basic info
-	lines = append(lines, fmt.Sprintf("ID: %s", m.fullSession.ID))
-	lines = append(lines, fmt.Sprintf("Messages: %d", m.fullSession.MessageCount))
-	lines = append(lines, fmt.Sprintf("Cost: $%.4f", m.fullSession.TotalCostUSD))
-	lines = append(lines, "")
-	
-	// Summary
-	if m.fullSession.Summary != "" {
-		lines = append(lines, "Summary:")
-		wrapped := wrapText(m.fullSession.Summary, innerWidth-2)
-		for _, line := range wrapped {
-			lines = append(lines, "  "+line)
-		}
-		lines = append(lines, "")
-	}
+	lines = append(lines, m.detailsInfoLines(innerWidth)...)
 	
 	// Show search matches if searching
 	if m.searchQuery != "" {
 		// Find matches for current session
-		var currentMatches []search.Match
-		for _, result := range m.searchResults {
-			if result.SessionID == m.fullSession.ID {
-				currentMatches = result.Matches
-				break
-			}
-		}
+		currentMatches := m.currentMatches()
 		
 		if len(currentMatches) > 0 {
-			lines = append(lines, fmt.Sprintf("Search Matches (%d):", len(currentMatches)))
+			// Show as many matches as asked for and the pane has room for
+			limit := m.matchWindowFor(fitMatches(innerHeight, len(lines)))
+			scroll := clampScroll(m.matchScroll, len(currentMatches), limit)
+			
+			header := fmt.Sprintf("Search Matches (%d):", len(currentMatches))
+			if scroll > 0 {
+				last := scroll + limit
+				if last > len(currentMatches) {
+					last = len(currentMatches)
+				}
+				header = fmt.Sprintf("Search Matches (%d-%d of %d):", scroll+1, last, len(currentMatches))
+			}
+			lines = append(lines, header)
 			lines = append(lines, strings.Repeat("─", innerWidth-2))
 			
-			// Show up to 5 matches
+			pattern := m.queryPattern()
 			shown := 0
-			for _, match := range currentMatches {
-				if shown >= 5 {
-					lines = append(lines, fmt.Sprintf("  ... and %d more matches", len(currentMatches)-shown))
+			for _, match := range currentMatches[scroll:] {
+				if shown >= limit {
+					lines = append(lines, fmt.Sprintf("  ... and %d more matches", len(currentMatches)-scroll-shown))
 					break
 				}
 				
@@ -520,10 +1277,9 @@ func (m *Model) renderDetails(width, height int) string {
 					displayText = strings.TrimSpace(match.Text)
 				}
 				
-				// Ensure it fits within width
-				if len(displayText) > innerWidth-4 {
-					displayText = displayText[:innerWidth-7] + "..."
-				}
+				// Fit the width without cutting away the matched term
+				displayText = truncateAround(displayText, pattern, innerWidth-4)
+				displayText = m.theme.highlightTerms(displayText, []*regexp.Regexp{pattern}, "")
 				
 				lines = append(lines, fmt.Sprintf("  %s", displayText))
 				shown++
@@ -534,9 +1290,9 @@ func (m *Model) renderDetails(width, height int) string {
 	
 	// Resume command
 	lines = append(lines, "Resume:")
-	cmd := m.fullSession.GetResumeCommand()
+	cmd := m.resumeCommand()
 	if len(cmd)+2 > innerWidth {
-		cmd = cmd[:innerWidth-5] + "..."
+		cmd = truncateRunes(cmd, innerWidth-2)
 	}
 	lines = append(lines, infoStyle.Render("  "+cmd))
 	lines = append(lines, "")
@@ -545,28 +1301,30 @@ func (m *Model) renderDetails(width, height int) string {
 	usedLines := len(lines)
 	remainingLines := innerHeight - usedLines - 2 // -2 for JSON header
 	
-	if remainingLines > 3 { // Only show JSON if we have decent space
-		lines = append(lines, "Last Raw Message (Complete):")
-		lines = append(lines, "")
+	if remainingLines > 3 && m.detailsMode == DetailsBoth { // Only show JSON if we have decent space
+		raw := m.fullSession.LastRawMessages
+		if !m.expandRaw && len(raw) > 1 {
+			raw = raw[:1]
+		}
 		
-		if len(m.fullSession.LastRawMessages) > 0 {
-			// Pretty print JSON with limited lines
-			var prettyJSON bytes.Buffer
-			rawMsg := m.fullSession.LastRawMessages[0]
-			if err := json.Indent(&prettyJSON, []byte(rawMsg), "", "  "); err == nil {
-				jsonLines := strings.Split(prettyJSON.String(), "\n")
-				shown := 0
-				for _, line := range jsonLines {
-					if shown >= remainingLines-1 {
-						lines = append(lines, mutedTextStyle.Render("  ... (more)"))
-						break
-					}
-					if len(line) > innerWidth-2 {
-						line = line[:innerWidth-5] + "..."
-					}
-					lines = append(lines, mutedTextStyle.Render("  "+line))
-					shown++
+		if len(raw) > 1 {
+			// Newest first, each given an equal share of the space
+			lines = append(lines, fmt.Sprintf("Last %d Raw Messages (newest first):", len(raw)))
+			lines = append(lines, "")
+			share := remainingLines / len(raw)
+			for i, rawMsg := range raw {
+				if share < 3 {
+					lines = append(lines, mutedTextStyle.Render(fmt.Sprintf("  +%d more", len(raw)-i)))
+					break
 				}
+				lines = append(lines, mutedTextStyle.Render(fmt.Sprintf("  ── %d ──", i+1)))
+				lines = append(lines, rawMessageLines(rawMsg, share-1, innerWidth)...)
+			}
+		} else {
+			lines = append(lines, "Last Raw Message (Complete):")
+			lines = append(lines, "")
+			if len(raw) > 0 {
+				lines = append(lines, rawMessageLines(raw[0], remainingLines, innerWidth)...)
 			}
 		}
 	}
@@ -585,27 +1343,50 @@ func (m *Model) renderDetails(width, height int) string {
 	return detailsStyle.Width(width).Height(height).Render(content)
 }
 
+// rawMessageLines pretty prints a raw JSON line for the details pane in at
+// most limit lines, the last of them noting any that did not fit
+func rawMessageLines(rawMsg string, limit, width int) []string {
+	var prettyJSON bytes.Buffer
+	if err := json.Indent(&prettyJSON, []byte(parser.ElideImages(rawMsg)), "", "  "); err != nil {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(prettyJSON.String(), "\n") {
+		if len(lines) >= limit-1 {
+			lines = append(lines, mutedTextStyle.Render("  "+ellipsis+" (more)"))
+			break
+		}
+		line = truncateRunes(line, width-2)
+		lines = append(lines, mutedTextStyle.Render("  "+line))
+	}
+	return lines
+}
+
+// toggleExpandRaw switches the details pane between the last raw message
+// and the last details.rawMessages of them
+func (m *Model) toggleExpandRaw() tea.Cmd {
+	m.expandRaw = !m.expandRaw
+	if m.expandRaw {
+		return m.setStatus(fmt.Sprintf("Showing the last %d raw messages", m.config.Details.RawMessages))
+	}
+	return m.setStatus("Showing the last raw message")
+}
+
 func (m *Model) renderStatusBar() string {
-	var leftText string
+	// Leave a space before the version so nothing runs into it
+	leftWidth := m.width - lipgloss.Width(m.version) - 2
+	available := leftWidth - 1
 
-	// Show status message if present, otherwise show key hints
-	statusDuration := 3 * time.Second
-	// Show ripgrep warning for longer
-	if strings.Contains(m.statusMsg, "ripgrep") {
-		statusDuration = 10 * time.Second
-	}
-	if m.statusMsg != "" && time.Since(m.statusTimer) < statusDuration {
-		leftText = m.statusMsg
-	} else if m.searchState == SearchStateInput {
-		leftText = "[Tab/Enter] Navigate results  [Esc] Cancel  Type to search..."
-	} else if m.searchState == SearchStateResults {
-		leftText = "[↑↓] Navigate  [/] Edit search  [Esc] Clear search  [Enter] Copy"
+	// Show status message if present, otherwise as many key hints as fit
+	var leftText string
+	if m.statusVisible() {
+		leftText = truncateRunes(m.statusMsg, available)
 	} else {
-		leftText = "[↑↓] Navigate  [Enter] Copy  [/] Search  [r] Refresh  [q] Quit"
+		leftText = fitHints(m.statusHints(), available)
 	}
 
 	// Create left and right content sections
-	leftStyle := keyHelpStyle.Width(m.width - lipgloss.Width(m.version) - 2)
+	leftStyle := keyHelpStyle.Width(leftWidth)
 	rightStyle := keyHelpStyle.Align(lipgloss.Right)
 
 	leftContent := leftStyle.Render(leftText)
@@ -637,20 +1418,36 @@ func (m *Model) renderSearchBar() string {
 	}
 	
 	searchStyle := lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(paneBorder).
 		BorderForeground(borderColor).
 		Padding(0, 1).
 		Width(m.width - 2)
 	
 	searchIcon := "🔍 "
+	scope := m.effectiveScope()
+	if scope == SearchScopeProject && m.allProjects {
+		// The browsed list already holds every project
+		scope = SearchScopeAll
+	}
+	flags := []string{scope.String()}
+	if !m.ignoreCase {
+		flags = append(flags, "Aa")
+	}
+	if m.searchRecent > 0 && m.searchRecent < len(m.sessions) {
+		flags = append(flags, fmt.Sprintf("latest %d", m.searchRecent))
+	}
+	label := "Search (" + strings.Join(flags, ", ") + "): "
+	if m.filterMode {
+		label = "Filter: "
+	}
 	var prompt string
 	
 	if m.searchState == SearchStateInput {
 		// Show cursor when focused
-		prompt = searchIcon + "Search: " + m.searchInput.View()
+		prompt = searchIcon + label + m.searchInput.View()
 	} else {
 		// Show static text when unfocused
-		prompt = searchIcon + "Search: " + m.searchQuery + statusText
+		prompt = searchIcon + label + m.searchQuery + statusText
 		if m.searchState == SearchStateResults {
 			prompt += " [Press / to edit]"
 		}
@@ -660,9 +1457,10 @@ func (m *Model) renderSearchBar() string {
 }
 
 func (m *Model) ensureVisible() {
-	// Calculate actual visible items (accounting for title and padding)
+	// Calculate actual visible lines (accounting for title and padding)
 	innerHeight := m.height - 1 - 5 // -1 for status, -5 for borders/padding/margins
 	itemsHeight := innerHeight - 2  // -2 for title and blank line
+	innerWidth := m.listPaneWidth() - 4
 	
 	if itemsHeight < 1 {
 		itemsHeight = 1
@@ -671,15 +1469,12 @@ func (m *Model) ensureVisible() {
 	// Adjust scroll to keep selection visible
 	if m.selected < m.scrollOffset {
 		m.scrollOffset = m.selected
-	} else if m.selected >= m.scrollOffset + itemsHeight {
-		m.scrollOffset = m.selected - itemsHeight + 1
+	} else if m.selected >= m.listVisibleEnd(m.scrollOffset, itemsHeight, innerWidth) {
+		m.scrollOffset = m.listScrollToShow(m.selected, itemsHeight, innerWidth)
 	}
 	
 	// Ensure scroll offset is valid
-	maxScroll := len(m.filteredSessions) - itemsHeight
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
+	maxScroll := m.maxListScroll(itemsHeight, innerWidth)
 	if m.scrollOffset > maxScroll {
 		m.scrollOffset = maxScroll
 	}
@@ -689,39 +1484,343 @@ func (m *Model) ensureVisible() {
 }
 
 func (m *Model) loadSessions() tea.Cmd {
+	m.loadGen++
+	m.refreshing = true
+
+	gen := m.loadGen
+	claudeDir := m.claudeDir
+	claudeConfig := m.claudeConfig
+	if m.repoOnly {
+		rootDir := m.rootDir()
+		repo := m.repo
+		return func() tea.Msg {
+			sessions, err := m.parser.ListAllSessions(rootDir)
+			return sessionsLoadedMsg{sessions: repoSessions(sessions, repo), active: claudeconfig.ActiveSessions(claudeConfig), err: err, gen: gen}
+		}
+	}
+	if m.allProjects {
+		rootDir := m.rootDir()
+		return func() tea.Msg {
+			sessions, err := m.parser.ListAllSessions(rootDir)
+			return sessionsLoadedMsg{sessions: sessions, active: claudeconfig.ActiveSessions(claudeConfig), err: err, gen: gen}
+		}
+	}
 	return func() tea.Msg {
-		sessions, err := m.parser.ListSessions(m.claudeDir)
-		return sessionsLoadedMsg{sessions: sessions, err: err}
+		sessions, err := m.parser.ListSessions(claudeDir)
+		return sessionsLoadedMsg{sessions: sessions, active: claudeconfig.ActiveSessions(claudeConfig), err: err, gen: gen}
 	}
 }
 
+// refresh reloads the session list, ignoring the request while a load is
+// already in flight so repeated presses do not race each other
+func (m *Model) refresh() tea.Cmd {
+	if m.refreshing {
+		return nil
+	}
+	m.loading = true
+	m.resetSearch()
+	m.scopeCache = make(map[SearchScope][]model.SessionInfo)
+	return m.loadSessions()
+}
+
+// previewSelected loads the newly selected session, waiting for the
+// configured preview delay first so scrolling quickly past sessions does not
+// parse every one of them
+func (m *Model) previewSelected() tea.Cmd {
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	
+	m.selectionGen++
+	filePath := m.filteredSessions[m.selected].FilePath
+	delay := time.Duration(m.config.List.PreviewDelayMs) * time.Millisecond
+	if delay <= 0 {
+		return m.loadFullSession(filePath)
+	}
+	
+	gen := m.selectionGen
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return previewTickMsg{gen: gen, filePath: filePath}
+	})
+}
+
 func (m *Model) loadFullSession(filePath string) tea.Cmd {
 	return func() tea.Msg {
 		session, err := m.parser.ParseFullSession(filePath)
-		return fullSessionLoadedMsg{session: session, err: err}
+		return fullSessionLoadedMsg{filePath: filePath, session: session, err: err}
 	}
 }
 
 // Messages
 type sessionsLoadedMsg struct {
 	sessions []model.SessionInfo
+	active   map[string]bool // sessions Claude Code's config names as active
 	err      error
+	gen      int // loadGen at dispatch time
 }
 
 type fullSessionLoadedMsg struct {
-	session *model.FullSession
-	err     error
+	filePath string // the file asked for, also set when parsing failed
+	session  *model.FullSession
+	err      error
 }
 
-type clearStatusMsg struct{}
+// clearStatusMsg expires the status message that was set at timer
+type clearStatusMsg struct {
+	timer time.Time
+}
+
+// previewTickMsg fires when a selection has been held for the preview delay
+type previewTickMsg struct {
+	gen      int
+	filePath string
+}
 
 type searchCompleteMsg struct {
-	results []search.SearchResult
-	query   string
-	err     error
+	results  []search.SearchResult
+	sessions []model.SessionInfo // what results' SessionIndex points into
+	query    string
+	opts     search.SearchOptions
+	scope    SearchScope
+	filter   bool // a name filter rather than a content search
+	listed   bool // sessions were listed for this search and can be cached
+	err      error
+}
+
+// sessionLabel returns the text shown for a session in title or preview
+// mode, or "" when the list should show the ID
+func (m *Model) sessionLabel(session model.SessionInfo) string {
+	switch m.listDisplay {
+	case ListDisplayTitle:
+		return session.Title
+	case ListDisplayPreview:
+		return session.Preview
+	}
+	return ""
+}
+
+// copyResumeCommand puts the resume command for the selected session on the
+// clipboard, at the first listed search match when copy.matchTemplate is set
+func (m *Model) copyResumeCommand() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+	if !m.detailsCurrent() {
+		return m.setStatus("Still loading the selected session")
+	}
+	return m.copyResume(m.resumeCommand())
+}
+
+// copyCompactResume puts the copy.compactTemplate resume command for the
+// selected session on the clipboard, for picking a long session back up
+// with its context compacted
+func (m *Model) copyCompactResume() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+	if !m.detailsCurrent() {
+		return m.setStatus("Still loading the selected session")
+	}
+	if m.config.Copy.CompactTemplate == "" {
+		return m.setStatus("Set copy.compactTemplate to copy compacting resume commands")
+	}
+	return m.copyResume(sessionLink(m.config.Copy.CompactTemplate, m.filteredSessions[m.selected]))
+}
+
+// copyResume copies a resume command for the loaded session and remembers
+// the session as resumed
+func (m *Model) copyResume(text string) tea.Cmd {
+	if m.config.Copy.TrailingNewline {
+		text += "\n"
+	}
+	if err := m.clipboardMgr.Copy(text); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	if err := m.state.MarkResumed(m.fullSession.ID, time.Now()); err != nil {
+		return m.setStatus(fmt.Sprintf("Copied, but could not save resume history: %v", err))
+	}
+	if cmd := m.markViewed(m.fullSession.ID, m.fullSession.MessageCount); cmd != nil {
+		return cmd
+	}
+	if !m.checkClaude() {
+		return m.setStatusFor("Copied, but claude is not on PATH here", warningStatusDuration)
+	}
+	return m.copied("Copied to clipboard!", m.fullSession.FilePath)
+}
+
+// markOpened remembers when the session's details were last opened, for
+// the recently viewed sort. SaveState writes it out on exit.
+func (m *Model) markOpened(sessionID string) {
+	if m.pickMode {
+		return
+	}
+	m.state.MarkOpened(sessionID, time.Now())
+	m.openedChanged = true
+}
+
+// SaveState writes when sessions were opened, which is only kept in memory
+// while browsing, dropping sessions no longer under the projects root
+func (m *Model) SaveState() error {
+	if !m.openedChanged {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(m.rootDir(), "*", "*.jsonl"))
+	if err == nil && len(files) > 0 {
+		ids := make(map[string]bool, len(files))
+		for _, file := range files {
+			ids[model.GetSessionID(file)] = true
+		}
+		m.state.PruneOpened(func(id string) bool { return ids[id] })
+	}
+	m.openedChanged = false
+	return m.state.Save()
+}
+
+// markViewed clears the session's unread flag by remembering its message
+// count, returning a status command only when that could not be saved
+func (m *Model) markViewed(sessionID string, messages int) tea.Cmd {
+	if m.pickMode {
+		return nil
+	}
+	m.invalidateList()
+	if err := m.state.MarkViewed(sessionID, messages); err != nil {
+		return m.setStatus(fmt.Sprintf("Could not save viewed sessions: %v", err))
+	}
+	return nil
+}
+
+// copyProjectPath puts the decoded filesystem path of the current project on
+// the clipboard. The decoding is best-effort, so say so when the path it
+// produces does not exist.
+func (m *Model) copyProjectPath() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	dir := m.claudeDir
+	if m.spansProjects() && m.selected < len(m.filteredSessions) {
+		// The repo and all-projects views span projects; use the selected session's
+		dir = filepath.Dir(m.filteredSessions[m.selected].FilePath)
+	} else if parser.IsSessionFile(dir) {
+		// Browsing one session file: its project is the directory it is in
+		dir = filepath.Dir(dir)
+	}
+	path := model.DecodeProjectPath(filepath.Base(dir))
+	if err := m.clipboardMgr.Copy(path); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	if _, err := os.Stat(path); err != nil {
+		return m.setStatus(fmt.Sprintf("Copied %s (best guess, path not found)", path))
+	}
+	return m.copied("Copied "+path, "")
+}
+
+// copyLastMessageJSON puts the session's last raw JSON line on the
+// clipboard, indented unless copy.rawJson is set
+func (m *Model) copyLastMessageJSON() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil || len(m.fullSession.LastRawMessages) == 0 {
+		return m.setStatus("No message to copy")
+	}
+	if !m.detailsCurrent() {
+		return m.setStatus("Still loading the selected session")
+	}
+	text := m.fullSession.LastRawMessages[0]
+	if !m.config.Copy.RawJSON {
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(text), "", "  "); err == nil {
+			text = pretty.String()
+		}
+	}
+	if err := m.clipboardMgr.Copy(text); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.copied("Copied last message JSON", m.fullSession.FilePath)
+}
+
+// copyMarkdownSummary puts a Markdown block with the session's title, ID,
+// model, cost, tokens and summary on the clipboard
+func (m *Model) copyMarkdownSummary() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+	if !m.detailsCurrent() {
+		return m.setStatus("Still loading the selected session")
+	}
+	title := m.filteredSessions[m.selected].Title
+	if err := m.clipboardMgr.Copy(export.Summary(title, m.fullSession)); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.copied("Copied Markdown summary", m.fullSession.FilePath)
+}
+
+// cycleListDisplay rotates the list between IDs, titles and previews
+func (m *Model) cycleListDisplay() tea.Cmd {
+	m.listDisplay = (m.listDisplay + 1) % listDisplayCount
+	return m.setStatus("List shows " + m.listDisplay.String())
 }
 
 // Helper functions
+
+// truncateAround cuts text to width runes keeping the first match of
+// pattern in view, centered when there is room, with the ellipsis marking
+// the cut on either side. Without a match it truncates like truncateRunes.
+func truncateAround(text string, pattern *regexp.Regexp, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	var loc []int
+	if pattern != nil {
+		loc = pattern.FindStringIndex(text)
+	}
+	mark := ellipsisWidth()
+	if loc == nil || width <= 2*mark {
+		return truncateRunes(text, width)
+	}
+
+	matchStart := utf8.RuneCountInString(text[:loc[0]])
+	matchLen := utf8.RuneCountInString(text[loc[0]:loc[1]])
+	room := width - 2*mark // both ellipses
+	start := matchStart - (room-matchLen)/2
+	if matchLen > room {
+		start = matchStart
+	}
+
+	switch {
+	case start <= 0:
+		return string(runes[:width-mark]) + ellipsis
+	case start+room >= len(runes):
+		return ellipsis + string(runes[len(runes)-width+mark:])
+	}
+	return ellipsis + string(runes[start:start+room]) + ellipsis
+}
+
+func truncateRunes(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	mark := ellipsisWidth()
+	if width <= mark {
+		return string(runes[:width])
+	}
+	return string(runes[:width-mark]) + ellipsis
+}
+
 func wrapText(text string, width int) []string {
 	if width <= 0 {
 		return []string{text}
@@ -753,6 +1852,20 @@ func wrapText(text string, width int) []string {
 	return lines
 }
 
+// formatSize renders a byte count with a binary unit, e.g. "1.2 MB"
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
 func getRelativeTime(t time.Time) string {
 	diff := time.Since(t)
 	
@@ -797,18 +1910,227 @@ func getRelativeTime(t time.Time) string {
 	}
 }
 
+// toggleShortIDs switches the list between full and shortened session IDs
+func (m *Model) toggleShortIDs() tea.Cmd {
+	m.shortIDs = !m.shortIDs
+	if m.shortIDs {
+		return m.setStatus("Showing short session IDs")
+	}
+	return m.setStatus("Showing full session IDs")
+}
+
+// toggleShowSize shows or hides file sizes in the list
+func (m *Model) toggleShowSize() tea.Cmd {
+	m.showSize = !m.showSize
+	if m.showSize {
+		return m.setStatus("Showing file sizes")
+	}
+	return m.setStatus("Hiding file sizes")
+}
+
+// toggleShowCount shows or hides message counts in the list
+func (m *Model) toggleShowCount() tea.Cmd {
+	m.showCount = !m.showCount
+	if m.showCount {
+		return m.setStatus("Showing message counts")
+	}
+	return m.setStatus("Hiding message counts")
+}
+
+// togglePosition shows or hides the session's place in the list beside the
+// details title
+func (m *Model) togglePosition() tea.Cmd {
+	m.showPosition = !m.showPosition
+	if m.showPosition {
+		return m.setStatus("Showing session position")
+	}
+	return m.setStatus("Hiding session position")
+}
+
+// refreshFiltered rebuilds the visible list from the search results, or from
+// every session when no search is active, leaving out empty sessions if
+// hidden. The recently resumed view keeps only resumed sessions, latest first.
+func (m *Model) refreshFiltered() {
+	m.invalidateList()
+
+	source := m.sessions
+	if m.searchQuery != "" {
+		source = make([]model.SessionInfo, 0, len(m.searchResults))
+		for _, result := range m.searchResults {
+			if result.SessionIndex < len(m.searchSessions) {
+				source = append(source, m.searchSessions[result.SessionIndex])
+			}
+		}
+	}
+	if !m.hideEmpty && !m.showResumed && m.datePreset == DateAll {
+		m.filteredSessions = source
+		m.sortFiltered()
+		m.orderActiveFirst()
+		return
+	}
+
+	since := m.datePreset.since(time.Now())
+	m.filteredSessions = make([]model.SessionInfo, 0, len(source))
+	for _, session := range source {
+		if m.hideEmpty && session.MessageCount == 0 {
+			continue
+		}
+		if m.datePreset != DateAll && session.LastActive.Before(since) {
+			continue
+		}
+		if _, ok := m.state.Resumed[session.ID]; m.showResumed && !ok {
+			continue
+		}
+		m.filteredSessions = append(m.filteredSessions, session)
+	}
+	if m.showResumed {
+		sort.SliceStable(m.filteredSessions, func(i, j int) bool {
+			return m.state.Resumed[m.filteredSessions[i].ID].After(m.state.Resumed[m.filteredSessions[j].ID])
+		})
+	}
+	m.sortFiltered()
+	m.orderActiveFirst()
+}
+
+// toggleResumed switches between all sessions and the recently resumed view
+func (m *Model) toggleResumed() tea.Cmd {
+	m.showResumed = !m.showResumed
+	m.refreshFiltered()
+	m.selected = 0
+	m.scrollOffset = 0
+
+	var statusCmd tea.Cmd
+	if m.showResumed {
+		statusCmd = m.setStatus("Showing recently resumed sessions")
+	} else {
+		statusCmd = m.setStatus("Showing all sessions")
+	}
+	return tea.Batch(statusCmd, m.syncSelection())
+}
+
+// toggleHideEmpty shows or hides sessions without messages, staying on the
+// selected session when it remains visible
+func (m *Model) toggleHideEmpty() tea.Cmd {
+	previousPath := m.selectedPath()
+	m.hideEmpty = !m.hideEmpty
+	m.refreshFiltered()
+
+	var statusCmd tea.Cmd
+	if m.hideEmpty {
+		statusCmd = m.setStatus("Hiding sessions without messages")
+	} else {
+		statusCmd = m.setStatus("Showing all sessions")
+	}
+	return m.reselect(previousPath, statusCmd)
+}
+
+// selectedPath is the file of the selected session, or empty when the list
+// is empty
+func (m *Model) selectedPath() string {
+	if m.selected < len(m.filteredSessions) {
+		return m.filteredSessions[m.selected].FilePath
+	}
+	return ""
+}
+
+// reselect goes back to the session at previousPath after the list was
+// refiltered, or to the top when it is gone, loading its details if the
+// selection changed
+func (m *Model) reselect(previousPath string, statusCmd tea.Cmd) tea.Cmd {
+	m.selected = 0
+	for i, session := range m.filteredSessions {
+		if session.FilePath == previousPath {
+			m.selected = i
+			break
+		}
+	}
+	return tea.Batch(statusCmd, m.syncSelection())
+}
+
+// detailsCurrent reports whether the loaded details are the selected
+// session's, and not one left over while the selection's details load
+func (m *Model) detailsCurrent() bool {
+	return m.fullSession != nil && m.fullSession.FilePath == m.selectedPath()
+}
+
+// syncSelection brings the selection back in line after the list changed:
+// it keeps the selected row and scroll offset within the list, clears the
+// details when nothing is listed and loads the selected session's details
+// when they show another session. Anything that refilters the list goes
+// through here so actions on the details never act on a session that is no
+// longer selected.
+func (m *Model) syncSelection() tea.Cmd {
+	if m.selected >= len(m.filteredSessions) {
+		m.selected = len(m.filteredSessions) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	m.ensureVisible()
+
+	if len(m.filteredSessions) == 0 {
+		m.fullSession = nil
+		return nil
+	}
+	filePath := m.filteredSessions[m.selected].FilePath
+	if m.fullSession != nil && m.fullSession.FilePath == filePath {
+		return nil
+	}
+	return m.loadFullSession(filePath)
+}
+
+// SetInitialSearch runs a content search for query as soon as the list
+// loads, starting on its results
+func (m *Model) SetInitialSearch(query string) {
+	m.pendingSearch = query
+}
+
+// startPendingSearch runs the search asked for at startup, once
+func (m *Model) startPendingSearch() tea.Cmd {
+	if m.pendingSearch == "" {
+		return nil
+	}
+	m.searchQuery = m.pendingSearch
+	m.pendingSearch = ""
+	m.searchInput.SetValue(m.searchQuery)
+	m.searchState = SearchStateResults
+	return tea.Batch(m.setStatus("Searching..."), m.performSearchCmd())
+}
+
 // Search helper methods
-func (m *Model) enterSearchMode() {
-	// Check if ripgrep is available
-	if !m.checkRipgrep() {
-		m.statusMsg = "Warning: ripgrep (rg) not found. Install it for search to work."
-		m.statusTimer = time.Now()
-		// Still enter search mode but user is warned
+func (m *Model) enterSearchMode() tea.Cmd {
+	m.copyTop = false
+	var clearCmd tea.Cmd
+	if m.filterMode {
+		// Switching kinds: a name filter's query means nothing to a search
+		clearCmd = m.clearSearch()
 	}
-	
 	m.searchState = SearchStateInput
 	m.searchInput.Focus()
 	m.searchInput.SetValue(m.searchQuery) // Keep existing query if any
+	m.historyIndex = len(m.searchHistory.Entries())
+	
+	// Check if ripgrep is available when it was asked for; otherwise the
+	// built-in search covers for it
+	if m.searchBackend == search.BackendRipgrep && !m.checkRipgrep() {
+		// Still enter search mode but user is warned, for longer than usual
+		return tea.Batch(clearCmd, textinput.Blink, m.setStatusFor("Warning: ripgrep (rg) not found. Install it for search to work.", warningStatusDuration))
+	}
+	return tea.Batch(clearCmd, textinput.Blink)
+}
+
+// enterFilterMode starts fuzzy-filtering the project's sessions by title and
+// ID as you type. It reads no session content, so ripgrep is never needed.
+func (m *Model) enterFilterMode() tea.Cmd {
+	var clearCmd tea.Cmd
+	if !m.filterMode {
+		clearCmd = m.clearSearch()
+		m.filterMode = true
+	}
+	m.searchState = SearchStateInput
+	m.searchInput.Focus()
+	m.searchInput.SetValue(m.searchQuery)
+	return tea.Batch(clearCmd, textinput.Blink)
 }
 
 func (m *Model) checkRipgrep() bool {
@@ -816,38 +2138,304 @@ func (m *Model) checkRipgrep() bool {
 	return err == nil
 }
 
-func (m *Model) clearSearch() {
+// checkClaude reports whether the copied resume command can run here
+func (m *Model) checkClaude() bool {
+	_, err := exec.LookPath("claude")
+	return err == nil
+}
+
+// warnMissingClaude warns once at startup when claude is not on PATH.
+// Copying still works, e.g. to paste on another machine.
+func (m *Model) warnMissingClaude() tea.Cmd {
+	if m.pickMode || m.checkClaude() {
+		return nil
+	}
+	return m.setStatusFor("Warning: claude not found on PATH. Copied resume commands won't run here.", warningStatusDuration)
+}
+
+// detailsSize is the width and height the details pane is rendered at
+func (m *Model) detailsSize() (width, height int) {
+	// Reserve space for status bar and search bar if active
+	reservedHeight := 1 // status bar
+	if m.searchState != SearchStateNormal {
+		reservedHeight += 3 // search bar with border
+	}
+	// Right pane gets remaining width minus the left margin
+	width = m.width - m.listPaneWidth() - 1
+	if m.showSidebar() {
+		width -= sidebarWidth
+	}
+	return width, m.height - reservedHeight
+}
+
+// fitMatches is how many matches a details pane innerHeight lines tall has
+// room for below used lines, keeping space for the "more" line and the
+// resume command
+func fitMatches(innerHeight, used int) int {
+	fit := innerHeight - used - 8
+	if fit < 1 {
+		return 1
+	}
+	return fit
+}
+
+// matchWindow is how many matches the details pane lists at once
+func (m *Model) matchWindow() int {
+	if m.fullSession == nil || m.height == 0 {
+		return m.matchWindowFor(0)
+	}
+	width, height := m.detailsSize()
+	// Same insets as renderDetails
+	return m.matchWindowFor(fitMatches(height-5, len(m.detailsInfoLines(width-4))))
+}
+
+// matchWindowFor is the number of matches asked for, cut to fit when the
+// pane has room for fewer (fit 0 means unknown)
+func (m *Model) matchWindowFor(fit int) int {
+	limit := m.matchPreviewCount
+	if fit > 0 && limit > fit {
+		limit = fit
+	}
+	return limit
+}
+
+// clampScroll keeps scroll no further than the last full window of total
+// matches
+func clampScroll(scroll, total, window int) int {
+	if scroll > total-window {
+		scroll = total - window
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	return scroll
+}
+
+// clampMatchScroll keeps the match list scrolled no further than its last
+// full window
+func (m *Model) clampMatchScroll(total int) {
+	m.matchScroll = clampScroll(m.matchScroll, total, m.matchWindow())
+}
+
+// scrollMatches moves the details pane's match list by step matches
+func (m *Model) scrollMatches(step int) {
+	m.matchScroll += step
+	m.clampMatchScroll(len(m.currentMatches()))
+}
+
+// showMatch scrolls the details pane's match list just far enough to bring
+// the match at index into view, e.g. the first or the last one
+func (m *Model) showMatch(index int) {
+	matches := m.currentMatches()
+	if index >= len(matches) {
+		index = len(matches) - 1
+	}
+	if index < m.matchScroll {
+		m.matchScroll = index
+	} else if window := m.matchWindow(); index >= m.matchScroll+window {
+		m.matchScroll = index - window + 1
+	}
+	m.clampMatchScroll(len(matches))
+}
+
+// defaultMatchPreviewCount is how many matches the details pane lists
+// until +/- change it
+const defaultMatchPreviewCount = 5
+
+// resizeMatchPreview lists step more (or fewer) matches in the details
+// pane. The count asked for is kept for later sessions even where fewer
+// matches show, so fewer starts from what is shown now.
+func (m *Model) resizeMatchPreview(step int) tea.Cmd {
+	count := m.matchPreviewCount
+	if step < 0 {
+		count = m.matchWindow()
+		if total := len(m.currentMatches()); total > 0 && total < count {
+			count = total
+		}
+	}
+	count += step
+	if count < 1 {
+		count = 1
+	}
+	m.matchPreviewCount = count
+	m.clampMatchScroll(len(m.currentMatches()))
+	return m.setStatusFor(fmt.Sprintf("Showing up to %d matches", count), copyStatusDuration)
+}
+
+// clearSearch leaves search or filter mode and lists every session again
+// from the top, loading the details of the session now selected
+func (m *Model) clearSearch() tea.Cmd {
+	m.resetSearch()
+	return m.syncSelection()
+}
+
+// resetSearch leaves search or filter mode and lists every session again
+// from the top. It leaves the details alone, for callers about to reload
+// the list, which selects and loads a session itself.
+func (m *Model) resetSearch() {
 	m.searchState = SearchStateNormal
 	m.searchInput.Blur()
 	m.searchInput.SetValue("")
 	m.searchQuery = ""
+	m.filterMode = false
 	m.searchResults = nil
+	m.resultsQuery = ""
+	m.copyTop = false
+	m.searchSessions = nil
 	// Reset to show all sessions
-	m.filteredSessions = m.sessions
+	m.refreshFiltered()
 	m.selected = 0
 	m.scrollOffset = 0
 }
 
 func (m *Model) performSearchCmd() tea.Cmd {
+	if m.filterMode {
+		return m.performFilterCmd()
+	}
+	query := m.searchQuery
+	opts := m.searchOptions()
+	scope := m.effectiveScope()
+	
+	// Sessions outside the browsed project get their own engine
+	engine := m.searchEngine
+	sessions := m.sessions
+	if scope != SearchScopeProject {
+		engine = nil
+		sessions = m.scopeCache[scope]
+	}
+	lister := m.scopeLister(scope)
+	
 	return func() tea.Msg {
-		if m.searchEngine == nil || m.searchQuery == "" {
-			return searchCompleteMsg{
-				results: []search.SearchResult{},
-				query:   m.searchQuery,
-				err:     nil,
+		msg := searchCompleteMsg{query: query, opts: opts, scope: scope}
+		if lister != nil {
+			msg.listed = true
+			var err error
+			var skipped *parser.SkippedError
+			sessions, err = lister()
+			if errors.As(err, &skipped) {
+				log.Printf("search %s: %v", scope, err)
+			} else if err != nil {
+				msg.err = err
+				return msg
 			}
 		}
+		msg.sessions = sessions
+		
+		if query == "" || (engine == nil && len(sessions) == 0) {
+			msg.results = []search.SearchResult{}
+			return msg
+		}
+		if engine == nil {
+			engine = search.NewEngineWithIndex(sessions, m.searchBackend, m.searchIndex)
+		}
 		
 		// Perform FULL TEXT SEARCH across all session content
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 		defer cancel()
 		
-		results, err := m.searchEngine.Search(ctx, m.searchQuery, search.SearchTypeContent)
-		
-		return searchCompleteMsg{
-			results: results,
-			query:   m.searchQuery,
-			err:     err,
+		msg.results, msg.err = engine.Search(ctx, query, search.SearchTypeContent, opts)
+		return msg
+	}
+}
+
+// performFilterCmd fuzzy-matches the query against the project's session
+// titles and IDs
+func (m *Model) performFilterCmd() tea.Cmd {
+	query := m.searchQuery
+	opts := m.searchOptions()
+	scope := m.effectiveScope()
+	engine := m.searchEngine
+	sessions := m.sessions
+	
+	return func() tea.Msg {
+		msg := searchCompleteMsg{query: query, opts: opts, scope: scope, filter: true, sessions: sessions}
+		if engine == nil && len(sessions) == 0 {
+			msg.results = []search.SearchResult{}
+			return msg
+		}
+		if engine == nil {
+			engine = search.NewEngineWithIndex(sessions, m.searchBackend, m.searchIndex)
+		}
+		msg.results, msg.err = engine.Search(context.Background(), query, search.SearchTypeFilter, opts)
+		return msg
+	}
+}
+
+// recallHistory steps through past queries (-1 older, +1 newer) and runs
+// the recalled one. Stepping past the newest entry clears the field.
+func (m *Model) recallHistory(step int) tea.Cmd {
+	entries := m.searchHistory.Entries()
+	index := m.historyIndex + step
+	if index < 0 || index > len(entries) {
+		return nil
+	}
+	m.historyIndex = index
+	
+	query := ""
+	if index < len(entries) {
+		query = entries[index]
+	}
+	m.searchInput.SetValue(query)
+	m.searchQuery = query
+	
+	if query == "" {
+		m.searchResults = nil
+		m.resultsQuery = ""
+		m.refreshFiltered()
+		return m.syncSelection()
+	}
+	return tea.Batch(m.setStatus("Searching..."), m.performSearchCmd())
+}
+
+// searchOptions collects the per-query search settings
+func (m *Model) searchOptions() search.SearchOptions {
+	return search.SearchOptions{IgnoreCase: m.ignoreCase, Recent: m.searchRecent, Ellipsis: ellipsis}
+}
+
+// searchScopeNote tells the user a search skipped older sessions
+func (m *Model) searchScopeNote() string {
+	if m.searchRecent > 0 && m.searchRecent < len(m.sessions) {
+		return fmt.Sprintf(" in the latest %d sessions", m.searchRecent)
+	}
+	return ""
+}
+
+// defaultSearchRecent is the limit ctrl+r applies when none is configured
+const defaultSearchRecent = 50
+
+// toggleSearchRecent switches content search between every session and only
+// the latest ones, re-running the current query
+func (m *Model) toggleSearchRecent() tea.Cmd {
+	var statusCmd tea.Cmd
+	if m.searchRecent > 0 {
+		m.searchRecent = 0
+		statusCmd = m.setStatus("Searching all sessions")
+	} else {
+		m.searchRecent = m.config.Search.Recent
+		if m.searchRecent <= 0 {
+			m.searchRecent = defaultSearchRecent
 		}
+		statusCmd = m.setStatus(fmt.Sprintf("Searching the latest %d sessions only", m.searchRecent))
+	}
+	
+	if m.searchQuery == "" {
+		return statusCmd
+	}
+	return tea.Batch(statusCmd, m.performSearchCmd())
+}
+
+// toggleIgnoreCase flips case sensitivity for this run and re-runs the search
+func (m *Model) toggleIgnoreCase() tea.Cmd {
+	m.ignoreCase = !m.ignoreCase
+	var statusCmd tea.Cmd
+	if m.ignoreCase {
+		statusCmd = m.setStatus("Search is now case-insensitive")
+	} else {
+		statusCmd = m.setStatus("Search is now case-sensitive")
+	}
+	
+	if m.searchQuery == "" {
+		return statusCmd
 	}
+	return tea.Batch(statusCmd, m.performSearchCmd())
 }
\ No newline at end of file
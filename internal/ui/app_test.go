@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// Truncated match contexts keep the matched term, wherever it falls
+func TestTruncateAroundKeepsMatch(t *testing.T) {
+	pattern := regexp.MustCompile("(?i)needle")
+	filler := strings.Repeat("hay ", 20)
+	tests := map[string]string{
+		"start":  "needle " + filler,
+		"middle": filler + "needle " + filler,
+		"end":    filler + "NEEDLE",
+		"wide":   strings.Repeat("é", 50) + "needle" + strings.Repeat("ü", 50),
+	}
+	for name, text := range tests {
+		got := truncateAround(text, pattern, 30)
+		if n := utf8.RuneCountInString(got); n > 30 {
+			t.Errorf("%s: expected at most 30 runes, got %d: %q", name, n, got)
+		}
+		if !pattern.MatchString(got) {
+			t.Errorf("%s: match cut away: %q", name, got)
+		}
+	}
+
+	if got := truncateAround("short needle", pattern, 30); got != "short needle" {
+		t.Errorf("Expected text that fits to be unchanged, got %q", got)
+	}
+}
+
+// Regexp queries keep their match in view, as the search found it
+func TestQueryPatternMatchesRegexp(t *testing.T) {
+	m := &Model{searchQuery: "fix.*bug|crash", ignoreCase: true}
+	text := strings.Repeat("hay ", 20) + "Fix the login bug" + strings.Repeat(" hay", 20)
+	if got := truncateAround(text, m.queryPattern(), 30); !strings.Contains(got, "Fix the login bug") {
+		t.Errorf("Expected the regexp match kept, got %q", got)
+	}
+}
+
+// A one-column ellipsis leaves two more columns of text
+func TestTruncateWithCustomEllipsis(t *testing.T) {
+	defer setEllipsis("")
+	setEllipsis("…")
+	if got := truncateRunes("abcdefghij", 6); got != "abcde…" {
+		t.Errorf("Expected %q, got %q", "abcde…", got)
+	}
+	pattern := regexp.MustCompile("needle")
+	got := truncateAround(strings.Repeat("hay ", 20)+"needle"+strings.Repeat(" hay", 20), pattern, 20)
+	if n := utf8.RuneCountInString(got); n != 20 || !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected 20 runes cut on both sides, got %d: %q", n, got)
+	}
+}
+
+// Link templates fill in the session's ID, project, directory and file
+func TestSessionLink(t *testing.T) {
+	session := model.SessionInfo{ID: "abc", FilePath: "/p/-home-me-repo/abc.jsonl"}
+	got := sessionLink("x://{project}/{id}?cwd={cwd}&f={path}", session)
+	want := "x://-home-me-repo/abc?cwd=/home/me/repo&f=/p/-home-me-repo/abc.jsonl"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	session.Cwd = "/work"
+	if got := sessionLink("{cwd}", session); got != "/work" {
+		t.Errorf("Expected the recorded cwd, got %q", got)
+	}
+}
+
+// The default compact template resumes with /compact as the first prompt
+func TestCompactTemplate(t *testing.T) {
+	session := model.SessionInfo{ID: "abc", FilePath: "/p/-home-me-repo/abc.jsonl"}
+	want := `claude --resume abc "/compact"`
+	if got := sessionLink(config.Default().Copy.CompactTemplate, session); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// Match counts spread over the color scale relative to the most matches
+func TestMatchCountStyle(t *testing.T) {
+	theme := newTheme(config.ThemeConfig{MatchColors: []string{"1", "2", "3", "4"}})
+	for count, want := range map[int]string{1: "1", 5: "1", 6: "2", 10: "2", 15: "3", 16: "4", 20: "4"} {
+		style, ok := theme.matchCountStyle(count, 20)
+		if !ok {
+			t.Fatalf("Expected a style for %d matches", count)
+		}
+		if got := style.GetForeground(); got != lipgloss.Color(want) {
+			t.Errorf("%d of 20 matches: expected color %s, got %v", count, want, got)
+		}
+	}
+	if _, ok := newTheme(config.ThemeConfig{}).matchCountStyle(3, 5); ok {
+		t.Error("Expected plain counts without match colors")
+	}
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// CopyFeedback is how a successful copy is confirmed
+type CopyFeedback int
+
+const (
+	CopyFeedbackTimed   CopyFeedback = iota // A status message for a couple of seconds
+	CopyFeedbackInstant                     // Nothing; the copy just happens
+	CopyFeedbackBadge                       // A lasting mark on the copied session's row
+)
+
+// copiedBadge marks the row of the session copied last in badge mode
+const copiedBadge = "copied ✓"
+
+// parseCopyFeedback maps a config value to a CopyFeedback, defaulting to
+// timed
+func parseCopyFeedback(value string) CopyFeedback {
+	switch value {
+	case "instant":
+		return CopyFeedbackInstant
+	case "badge":
+		return CopyFeedbackBadge
+	}
+	return CopyFeedbackTimed
+}
+
+// copied confirms a successful copy the configured way. filePath is the
+// session the copy came from, or empty when it is not one session's;
+// badge mode falls back to the status message then.
+func (m *Model) copied(msg, filePath string) tea.Cmd {
+	switch m.copyFeedback {
+	case CopyFeedbackInstant:
+		return nil
+	case CopyFeedbackBadge:
+		if filePath != "" {
+			m.copiedPath = filePath
+			m.invalidateList()
+			return nil
+		}
+	}
+	return m.setStatusFor(msg, copyStatusDuration)
+}
+
+// toggleTrailingNewline switches whether the copied resume command ends
+// in a newline, which many terminals run as soon as it is pasted
+func (m *Model) toggleTrailingNewline() tea.Cmd {
+	m.config.Copy.TrailingNewline = !m.config.Copy.TrailingNewline
+	if m.config.Copy.TrailingNewline {
+		return m.setStatus("Resume command copies with a newline: pasting runs it")
+	}
+	return m.setStatus("Resume command copies without a newline: pasting waits for Enter")
+}
+
+// sessionLink fills a copy.linkTemplate or copy.compactTemplate for a
+// session. {id} is the session ID, {project} the encoded project
+// directory, {cwd} the directory the session ran in (decoded from the
+// project when not recorded) and {path} the session file. Claude Code has
+// no link scheme of its own, so the default opens the file.
+func sessionLink(template string, session model.SessionInfo) string {
+	project := filepath.Base(filepath.Dir(session.FilePath))
+	cwd := session.Cwd
+	if cwd == "" {
+		cwd = model.DecodeProjectPath(project)
+	}
+	return strings.NewReplacer(
+		"{id}", session.ID,
+		"{project}", project,
+		"{cwd}", cwd,
+		"{path}", session.FilePath,
+	).Replace(template)
+}
+
+// resumeCommand is the resume command Enter copies for the loaded session:
+// copy.matchTemplate at the first match listed in the details pane when
+// one is set and that match has a message UUID, the plain resume otherwise
+func (m *Model) resumeCommand() string {
+	if command := m.matchResume(); command != "" {
+		return command
+	}
+	return m.fullSession.GetResumeCommand()
+}
+
+// matchResume fills copy.matchTemplate for the selected session, with
+// {message} the UUID of the message the first listed match is in, or
+// returns "" when there is no template, match or UUID to fill it with
+func (m *Model) matchResume() string {
+	if m.config.Copy.MatchTemplate == "" || !m.detailsCurrent() {
+		return ""
+	}
+	matches := m.currentMatches()
+	if m.matchScroll >= len(matches) {
+		return ""
+	}
+	uuid := messageUUID(matches[m.matchScroll])
+	if uuid == "" {
+		return ""
+	}
+	template := strings.ReplaceAll(m.config.Copy.MatchTemplate, "{message}", uuid)
+	return sessionLink(template, m.filteredSessions[m.selected])
+}
+
+// messageUUID returns the uuid of the session entry a content match is on,
+// or "" when the matched line is not an entry that has one
+func messageUUID(match search.Match) string {
+	var entry struct {
+		UUID string `json:"uuid"`
+	}
+	if err := json.Unmarshal([]byte(match.Text), &entry); err != nil {
+		return ""
+	}
+	return entry.UUID
+}
+
+// copySessionLink puts a link to the selected session, built from
+// copy.linkTemplate, on the clipboard for editors and note apps
+func (m *Model) copySessionLink() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	if m.config.Copy.LinkTemplate == "" {
+		return m.setStatus("Set copy.linkTemplate to copy session links")
+	}
+	session := m.filteredSessions[m.selected]
+	link := sessionLink(m.config.Copy.LinkTemplate, session)
+	if err := m.clipboardMgr.Copy(link); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.copied("Copied "+link, session.FilePath)
+}
+
+// largeCopyBytes is the transcript size above which copying asks first;
+// some clipboard backends fail or hang on payloads this big
+const largeCopyBytes = 1 << 20
+
+// transcriptReadyMsg carries a session's plain-text transcript to copy
+type transcriptReadyMsg struct {
+	filePath string
+	text     string
+	err      error
+}
+
+// copyTranscript reads the selected session's messages into a plain-text,
+// role-headed transcript for the clipboard
+func (m *Model) copyTranscript() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+	filePath := m.fullSession.FilePath
+	return func() tea.Msg {
+		messages, err := m.parser.ParseMessages(filePath)
+		if err != nil {
+			return transcriptReadyMsg{filePath: filePath, err: err}
+		}
+		return transcriptReadyMsg{filePath: filePath, text: export.Text(messages)}
+	}
+}
+
+// handleTranscriptReady copies the transcript, unless it is large and Y was
+// not pressed again for the same session after the warning
+func (m *Model) handleTranscriptReady(msg transcriptReadyMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Could not read session: %v", msg.err))
+	}
+	if len(msg.text) > largeCopyBytes && m.largeCopyPath != msg.filePath {
+		m.largeCopyPath = msg.filePath
+		return m.setStatusFor(fmt.Sprintf("Transcript is %s, which some clipboards cannot take; press Y again to copy it", formatSize(int64(len(msg.text)))), warningStatusDuration)
+	}
+	m.largeCopyPath = ""
+	if err := m.clipboardMgr.Copy(msg.text); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.copied("Copied transcript ("+formatSize(int64(len(msg.text)))+")", msg.filePath)
+}
@@ -0,0 +1,47 @@
+package ui
+
+import "github.com/davidpaquet/claude-session-browser/internal/model"
+
+// CostDisplay controls whether costs are shown in the details pane and the
+// all-projects table
+type CostDisplay int
+
+const (
+	CostAuto CostDisplay = iota // Shown unless every session costs nothing
+	CostShow
+	CostHide
+)
+
+// parseCostDisplay maps a config value to a CostDisplay, defaulting to auto
+func parseCostDisplay(value string) CostDisplay {
+	switch value {
+	case "show":
+		return CostShow
+	case "hide":
+		return CostHide
+	}
+	return CostAuto
+}
+
+// showsCost reports whether costs belong on screen for a set of sessions,
+// given whether any of them recorded one
+func (d CostDisplay) showsCost(anyCost bool) bool {
+	switch d {
+	case CostShow:
+		return true
+	case CostHide:
+		return false
+	}
+	return anyCost
+}
+
+// anyCost reports whether any of the sessions recorded a cost. Setups that
+// never write costUSD would otherwise show $0 everywhere.
+func anyCost(sessions []model.SessionInfo) bool {
+	for _, session := range sessions {
+		if session.CostUSD > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DatePreset narrows the list to sessions active within a recent window
+type DatePreset int
+
+const (
+	DateAll   DatePreset = iota // No date filter
+	DateToday                   // Active since midnight
+	DateWeek                    // Active in the last 7 days, today included
+	DateMonth                   // Active in the last 30 days, today included
+	datePresetCount
+)
+
+func (p DatePreset) String() string {
+	switch p {
+	case DateToday:
+		return "Today"
+	case DateWeek:
+		return "Last 7 Days"
+	case DateMonth:
+		return "Last 30 Days"
+	}
+	return "All Dates"
+}
+
+// since is the start of the preset's window, counted in whole local days
+// back from now
+func (p DatePreset) since(now time.Time) time.Time {
+	days := map[DatePreset]int{DateToday: 0, DateWeek: 6, DateMonth: 29}[p]
+	y, mo, d := now.Date()
+	return time.Date(y, mo, d-days, 0, 0, 0, 0, now.Location())
+}
+
+// cycleDatePreset steps through today, the last week, the last month and
+// back to all sessions
+func (m *Model) cycleDatePreset() tea.Cmd {
+	return m.setDatePreset((m.datePreset + 1) % datePresetCount)
+}
+
+// setDatePreset filters the list to preset, staying on the selected
+// session when it is still listed
+func (m *Model) setDatePreset(preset DatePreset) tea.Cmd {
+	previousPath := m.selectedPath()
+	m.datePreset = preset
+	m.refreshFiltered()
+
+	status := "Showing sessions from all dates"
+	if preset != DateAll {
+		status = "Showing sessions active " + map[DatePreset]string{
+			DateToday: "today",
+			DateWeek:  "in the last 7 days",
+			DateMonth: "in the last 30 days",
+		}[preset]
+	}
+	return m.reselect(previousPath, m.setStatus(status))
+}
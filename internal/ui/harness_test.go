@@ -0,0 +1,663 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/state"
+)
+
+// cmdTimeout bounds how long the harness waits for a command's message
+// before failing the test
+const cmdTimeout = 10 * time.Second
+
+// harness drives a Model the way the Bubble Tea runtime does: every message
+// goes through Update and the commands it returns are run, feeding their
+// messages back in until none are left. Timers are dropped, as if no time
+// passed, so status messages stay up and previews load at once.
+type harness struct {
+	t    *testing.T
+	m    *Model
+	dir  string
+	quit bool
+}
+
+// testSession is a session file for the harness to write. The list orders
+// sessions by file time, which modTime sets.
+type testSession struct {
+	id      string
+	modTime time.Time
+	lines   []string
+}
+
+// newHarness starts the app on a project directory holding sessions and
+// waits for the list to load
+func newHarness(t *testing.T, sessions ...testSession) *harness {
+	t.Helper()
+	isolateConfig(t)
+	h := &harness{t: t, dir: t.TempDir()}
+	for _, session := range sessions {
+		h.writeSession(session)
+	}
+	h.start(h.dir)
+	return h
+}
+
+// isolateConfig points the config directory, where the app keeps its
+// state and search history, at a temporary one. os.UserConfigDir reads
+// XDG_CONFIG_HOME on Linux, HOME on macOS and AppData on Windows.
+func isolateConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+}
+
+// start opens the app on claudeDir, a project directory or session file
+func (h *harness) start(claudeDir string) {
+	h.t.Helper()
+	cfg := config.Default()
+	cfg.List.PreviewDelayMs = 0
+	cfg.Search.Backend = "go"
+	h.m = NewApp(claudeDir, "test", cfg)
+	h.send(tea.WindowSizeMsg{Width: 120, Height: 40})
+	h.run(h.m.Init())
+	if h.m.loading {
+		h.t.Fatal("Sessions did not load")
+	}
+}
+
+// writeSession writes a session file into the project directory
+func (h *harness) writeSession(session testSession) {
+	h.t.Helper()
+	path := filepath.Join(h.dir, session.id+".jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(session.lines, "\n")+"\n"), 0644); err != nil {
+		h.t.Fatal(err)
+	}
+	if err := os.Chtimes(path, session.modTime, session.modTime); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// send delivers a message and everything that follows from it
+func (h *harness) send(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case nil:
+		return
+	case tea.QuitMsg:
+		h.quit = true
+		return
+	case tea.BatchMsg:
+		h.runAll(msg)
+		return
+	}
+	_, cmd := h.m.Update(msg)
+	h.run(cmd)
+}
+
+// run runs a command, delivering its message if it comes in time
+func (h *harness) run(cmd tea.Cmd) {
+	if cmd != nil {
+		h.runAll([]tea.Cmd{cmd})
+	}
+}
+
+// runAll runs commands together, as a batch does, and delivers their
+// messages in order
+func (h *harness) runAll(cmds []tea.Cmd) {
+	h.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+
+	results := make([]chan tea.Msg, len(cmds))
+	for i, cmd := range cmds {
+		if cmd == nil || isTimer(cmd) {
+			continue
+		}
+		results[i] = make(chan tea.Msg, 1)
+		go func(cmd tea.Cmd, out chan<- tea.Msg) { out <- cmd() }(cmd, results[i])
+	}
+	var msgs []tea.Msg
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		select {
+		case msg := <-result:
+			msgs = append(msgs, msg)
+		case <-ctx.Done():
+			h.t.Fatalf("A command gave no message within %v", cmdTimeout)
+		}
+	}
+	for _, msg := range msgs {
+		h.send(msg)
+	}
+}
+
+// isTimer reports whether cmd only waits on the clock: tea.Tick, tea.Every
+// and the text input's cursor blink
+func isTimer(cmd tea.Cmd) bool {
+	name := runtime.FuncForPC(reflect.ValueOf(cmd).Pointer()).Name()
+	for _, timer := range []string{"bubbletea.Tick.", "bubbletea.Every.", "bubbles/cursor."} {
+		if strings.Contains(name, timer) {
+			return true
+		}
+	}
+	return false
+}
+
+// keys presses each key in turn: names such as "enter", "esc", "up" and
+// "ctrl+c", or text typed rune by rune
+func (h *harness) keys(keys ...string) {
+	special := map[string]tea.KeyType{
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "tab": tea.KeyTab,
+		"up": tea.KeyUp, "down": tea.KeyDown, "backspace": tea.KeyBackspace,
+		"ctrl+c": tea.KeyCtrlC, "ctrl+t": tea.KeyCtrlT,
+	}
+	for _, key := range keys {
+		if keyType, ok := special[key]; ok {
+			h.send(tea.KeyMsg{Type: keyType})
+			continue
+		}
+		for _, r := range key {
+			h.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+}
+
+// fakeClipboard puts an xclip on PATH that saves what is copied, and
+// returns a function reading it back
+func (h *harness) fakeClipboard() func() string {
+	h.t.Helper()
+	if runtime.GOOS != "linux" {
+		h.t.Skip("The fake clipboard stands in for xclip, which only Linux uses")
+	}
+	bin := h.t.TempDir()
+	saved := filepath.Join(bin, "clipboard")
+	script := "#!/bin/sh\ncat > '" + saved + "'\n"
+	if err := os.WriteFile(filepath.Join(bin, "xclip"), []byte(script), 0755); err != nil {
+		h.t.Fatal(err)
+	}
+	h.t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	h.t.Setenv("WAYLAND_DISPLAY", "")
+	return func() string {
+		data, _ := os.ReadFile(saved)
+		return string(data)
+	}
+}
+
+// listed returns the IDs in the list, in order
+func (h *harness) listed() []string {
+	var ids []string
+	for _, session := range h.m.filteredSessions {
+		ids = append(ids, session.ID)
+	}
+	return ids
+}
+
+// selectedID returns the selected session's ID
+func (h *harness) selectedID() string {
+	if h.m.selected >= len(h.m.filteredSessions) {
+		return ""
+	}
+	return h.m.filteredSessions[h.m.selected].ID
+}
+
+// userLine is a user message line
+func userLine(text string) string {
+	return `{"type":"user","message":{"role":"user","content":"` + text + `"}}`
+}
+
+// daysAgo is a file time the given number of days back
+func daysAgo(days int) time.Time {
+	return time.Now().AddDate(0, 0, -days)
+}
+
+// harnessSessions are three sessions, newest first: two hold "needle" and
+// one is about release notes
+func harnessSessions() []testSession {
+	return []testSession{
+		{"aaaa", daysAgo(1), []string{userLine("fix the login bug"), userLine("the needle is here")}},
+		{"bbbb", daysAgo(2), []string{userLine("write release notes")}},
+		{"cccc", daysAgo(3), []string{userLine("another needle and a needle")}},
+	}
+}
+
+// Typing a content search narrows the list as results come in, Enter moves
+// to navigating them and Esc goes back to every session
+func TestHarnessContentSearch(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	if got := strings.Join(h.listed(), ","); got != "aaaa,bbbb,cccc" {
+		t.Fatalf("Expected sessions newest first, got %s", got)
+	}
+
+	h.keys("/", "needle")
+	if h.m.searchState != SearchStateInput {
+		t.Fatalf("Expected search input, got state %v", h.m.searchState)
+	}
+	if got := strings.Join(h.listed(), ","); got != "aaaa,cccc" {
+		t.Errorf("Expected the sessions holding needle, got %s", got)
+	}
+
+	h.keys("enter")
+	if h.m.searchState != SearchStateResults {
+		t.Fatalf("Expected search results, got state %v", h.m.searchState)
+	}
+	view := h.m.View()
+	if !strings.Contains(view, "(2 matches)") || !strings.Contains(view, "[2]") {
+		t.Errorf("Expected the match count in the title and rows:\n%s", view)
+	}
+
+	h.keys("j")
+	if h.selectedID() != "cccc" {
+		t.Errorf("Expected j to move to cccc, got %s", h.selectedID())
+	}
+	if h.m.fullSession == nil || h.m.fullSession.ID != "cccc" {
+		t.Error("Expected the details to follow the selection")
+	}
+
+	h.keys("esc")
+	if h.m.searchState != SearchStateNormal || len(h.listed()) != 3 {
+		t.Errorf("Expected Esc to clear the search, got state %v with %v", h.m.searchState, h.listed())
+	}
+	if h.quit {
+		t.Error("Expected the app to keep running")
+	}
+}
+
+// The fuzzy filter narrows by title without searching content
+func TestHarnessFilter(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("f", "release")
+	if got := strings.Join(h.listed(), ","); got != "bbbb" {
+		t.Errorf("Expected only the release session, got %s", got)
+	}
+	h.keys("esc")
+	if len(h.listed()) != 3 {
+		t.Errorf("Expected Esc to clear the filter, got %v", h.listed())
+	}
+}
+
+// Filtering a project with no sessions lists nothing instead of failing
+func TestHarnessFilterEmptyProject(t *testing.T) {
+	h := newHarness(t)
+	h.keys("f", "release")
+	if len(h.listed()) != 0 || h.m.err != nil {
+		t.Errorf("Expected an empty list, got %v (%v)", h.listed(), h.m.err)
+	}
+}
+
+// + on a session with few matches raises the count for later sessions
+// instead of lowering it to what this one has
+func TestHarnessMatchPreviewCount(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("/", "needle", "enter", "+")
+	if h.m.matchPreviewCount != defaultMatchPreviewCount+1 {
+		t.Errorf("Expected + to ask for %d matches, got %d", defaultMatchPreviewCount+1, h.m.matchPreviewCount)
+	}
+	h.keys("-")
+	if h.m.matchPreviewCount != 1 {
+		t.Errorf("Expected - to step down from the one match shown, got %d", h.m.matchPreviewCount)
+	}
+}
+
+// A load that failed after the selection moved on leaves the current
+// details alone
+func TestHarnessStaleFailedLoad(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.send(fullSessionLoadedMsg{filePath: filepath.Join(h.dir, "bbbb.jsonl"), err: os.ErrPermission})
+	if h.m.fullSession == nil || h.m.fullSession.ID != "aaaa" || strings.Contains(h.m.statusMsg, "Error") {
+		t.Errorf("Expected aaaa's details kept, got %v (status %q)", h.m.fullSession, h.m.statusMsg)
+	}
+}
+
+// Refreshing reloads the list from disk, picking up new sessions and
+// starting again from the top
+func TestHarnessRefresh(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("j")
+	if h.selectedID() != "bbbb" {
+		t.Fatalf("Expected bbbb selected, got %s", h.selectedID())
+	}
+
+	h.writeSession(testSession{"dddd", time.Now(), []string{userLine("brand new")}})
+	h.keys("r")
+	if got := strings.Join(h.listed(), ","); got != "dddd,aaaa,bbbb,cccc" {
+		t.Errorf("Expected the new session listed first, got %s", got)
+	}
+	if h.selectedID() != "dddd" || h.m.fullSession == nil || h.m.fullSession.ID != "dddd" {
+		t.Errorf("Expected the new session selected and shown, got %s", h.selectedID())
+	}
+}
+
+// Enter copies the resume command for the selected session
+func TestHarnessCopyResume(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	clipboard := h.fakeClipboard()
+	h.keys("j", "enter")
+	if got := clipboard(); got != "claude --resume bbbb" {
+		t.Errorf("Expected the resume command copied, got %q (status %q)", got, h.m.statusMsg)
+	}
+	if _, ok := h.m.state.Resumed["bbbb"]; !ok {
+		t.Error("Expected the copied session remembered as resumed")
+	}
+}
+
+// q quits from the list but types into the search box
+func TestHarnessQuit(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("/", "q")
+	if h.quit || h.m.searchQuery != "q" {
+		t.Errorf("Expected q typed into the search, got query %q", h.m.searchQuery)
+	}
+	h.keys("esc", "q")
+	if !h.quit {
+		t.Error("Expected q to quit from the list")
+	}
+}
+
+// Narrowing the list past the selected row moves the selection onto a listed
+// session and shows its details, and an empty list shows none
+func TestHarnessSelectionFollowsFilter(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("j", "j")
+	if h.selectedID() != "cccc" {
+		t.Fatalf("Expected cccc selected, got %s", h.selectedID())
+	}
+
+	h.keys("f", "release")
+	if h.selectedID() != "bbbb" || h.m.fullSession == nil || h.m.fullSession.ID != "bbbb" {
+		t.Errorf("Expected the only match selected and shown, got %s", h.selectedID())
+	}
+
+	h.keys("zzz")
+	if len(h.listed()) != 0 || h.m.selected != 0 || h.m.scrollOffset != 0 {
+		t.Fatalf("Expected an empty list at the top, got %v at %d", h.listed(), h.m.selected)
+	}
+	if h.m.fullSession != nil {
+		t.Errorf("Expected no details with nothing listed, got %s", h.m.fullSession.ID)
+	}
+	h.keys("enter", "enter")
+	if _, ok := h.m.state.Resumed["cccc"]; ok {
+		t.Error("Expected Enter on an empty list to copy nothing")
+	}
+
+	h.keys("esc")
+	if h.selectedID() != "aaaa" || h.m.fullSession == nil || h.m.fullSession.ID != "aaaa" {
+		t.Errorf("Expected the top session selected and shown after Esc, got %s", h.selectedID())
+	}
+}
+
+// A search with no matches clears the details of the session it left
+func TestHarnessSearchWithoutMatches(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("j", "/", "nowhere")
+	if len(h.listed()) != 0 || h.m.fullSession != nil {
+		t.Errorf("Expected no sessions and no details, got %v", h.listed())
+	}
+	h.keys("backspace", "backspace", "backspace", "backspace", "backspace", "backspace", "backspace")
+	if len(h.listed()) != 3 || h.m.fullSession == nil || h.m.fullSession.ID != h.selectedID() {
+		t.Errorf("Expected every session back with the selection shown, got %v", h.listed())
+	}
+}
+
+// The built-in search matches an invalid regexp as plain text, and the
+// hints say so rather than blaming the pattern
+func TestHarnessInvalidRegexpHint(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("/", "needle(", "enter")
+	if hints := strings.Join(h.m.noMatchHints(), "\n"); !strings.Contains(hints, "searched for as plain text") {
+		t.Errorf("Expected the literal search explained, got %q", hints)
+	}
+}
+
+// The details title shows the selection's place in the list, counting only
+// the sessions a search leaves
+func TestHarnessDetailsPosition(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("j")
+	if view := h.m.View(); !strings.Contains(view, "Session 2 of 3") {
+		t.Errorf("Expected the position in the details title:\n%s", view)
+	}
+	h.keys("/", "needle", "enter", "j")
+	if view := h.m.View(); !strings.Contains(view, "Session 2 of 2") {
+		t.Errorf("Expected the position among the matches:\n%s", view)
+	}
+
+	h.m.showPosition = false
+	if view := h.m.View(); strings.Contains(view, "Session 2 of 2") {
+		t.Error("Expected no position when turned off")
+	}
+	h.m.showPosition = true
+	if title := h.m.detailsTitle(20); strings.Contains(title, "of 2") {
+		t.Errorf("Expected the position left off a narrow pane, got %q", title)
+	}
+}
+
+// Sessions opened in the browser sort most recently viewed first, the rest
+// after them
+func TestHarnessSortRecentlyViewed(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.keys("j", "j", "k")
+	opened := h.m.state.Opened
+	if !opened["bbbb"].After(opened["cccc"]) || !opened["cccc"].After(opened["aaaa"]) {
+		t.Fatalf("Expected each session opened in turn, got %v", opened)
+	}
+
+	if err := h.m.SaveState(); err != nil {
+		t.Fatalf("SaveState failed: %v", err)
+	}
+	saved, err := state.Load(state.DefaultPath())
+	if err != nil || len(saved.Opened) != 3 {
+		t.Errorf("Expected the opened times saved, got %v (%v)", saved.Opened, err)
+	}
+
+	h.m.sortKey = SortViewed
+	h.m.refreshFiltered()
+	if got := strings.Join(h.listed(), ","); got != "bbbb,cccc,aaaa" {
+		t.Errorf("Expected the latest viewed first, got %s", got)
+	}
+
+	h.writeSession(testSession{"dddd", time.Now(), []string{userLine("never opened")}})
+	h.keys("r")
+	if got := strings.Join(h.listed(), ","); got != "bbbb,cccc,aaaa,dddd" {
+		t.Errorf("Expected the session never viewed last, got %s", got)
+	}
+}
+
+// A session file opened in place of a project is a list of that session
+// alone, searched like any other
+func TestHarnessSingleFile(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.start(filepath.Join(h.dir, "cccc.jsonl"))
+	if got := strings.Join(h.listed(), ","); got != "cccc" {
+		t.Fatalf("Expected only the file's session, got %s", got)
+	}
+	if h.m.fullSession == nil || h.m.fullSession.ID != "cccc" {
+		t.Error("Expected the session's details shown")
+	}
+	h.keys("/", "needle", "enter")
+	if len(h.listed()) != 1 || !strings.Contains(h.m.View(), "[2]") {
+		t.Errorf("Expected the file's matches, got %v", h.listed())
+	}
+}
+
+// With search.enter set to copy, Enter in the search box copies the top
+// result, loading it first, while Tab only moves to the results
+func TestHarnessSearchEnterCopiesTop(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	clipboard := h.fakeClipboard()
+	h.m.searchEnter = SearchEnterCopy
+	h.keys("j", "j", "/", "needle", "tab")
+	if h.m.searchState != SearchStateResults || h.selectedID() != "cccc" || len(h.m.state.Resumed) != 0 {
+		t.Fatalf("Expected Tab to keep cccc selected and copy nothing, got %s", h.selectedID())
+	}
+
+	h.keys("/", "enter")
+	if h.selectedID() != "aaaa" || h.m.fullSession == nil || h.m.fullSession.ID != "aaaa" || h.m.copyTop {
+		t.Fatalf("Expected the top result selected and loaded, got %s", h.selectedID())
+	}
+	if got := clipboard(); got != "claude --resume aaaa" {
+		t.Errorf("Expected the top result copied, got %q (status %q)", got, h.m.statusMsg)
+	}
+}
+
+// When the top result cannot be read, Enter copies nothing, then or on a
+// later load
+func TestHarnessSearchEnterUnreadableTop(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	clipboard := h.fakeClipboard()
+	h.m.searchEnter = SearchEnterCopy
+	h.keys("j", "j", "/", "needle")
+	top := harnessSessions()[0]
+	if err := os.Remove(filepath.Join(h.dir, top.id+".jsonl")); err != nil {
+		t.Fatal(err)
+	}
+
+	h.keys("enter")
+	if h.m.copyTop || clipboard() != "" || !strings.Contains(h.m.statusMsg, "nothing copied") {
+		t.Fatalf("Expected the failed load to cancel the copy, got status %q", h.m.statusMsg)
+	}
+
+	h.writeSession(top)
+	h.run(h.m.loadFullSession(filepath.Join(h.dir, top.id+".jsonl")))
+	if got := clipboard(); got != "" || len(h.m.state.Resumed) != 0 {
+		t.Errorf("Expected a later load to copy nothing, got %q", got)
+	}
+}
+
+// The details show how much of a session's input the prompt cache served,
+// as does the sidebar on wide terminals
+func TestHarnessCacheHitRate(t *testing.T) {
+	usage := `"usage":{"input_tokens":10,"output_tokens":5,"cache_read_input_tokens":300,"cache_creation_input_tokens":90}`
+	h := newHarness(t, testSession{"aaaa", daysAgo(1), []string{
+		userLine("cache me"),
+		`{"type":"assistant","message":{"id":"msg_1","role":"assistant","content":"ok",` + usage + `}}`,
+	}})
+	if view := h.m.View(); !strings.Contains(view, "Cache hits: 75% of input") {
+		t.Errorf("Expected the cache hit rate in the details:\n%s", view)
+	}
+
+	h.send(tea.WindowSizeMsg{Width: 200, Height: 40})
+	if view := h.m.View(); !strings.Contains(view, "Cache hits") || !strings.Contains(view, "75%") || strings.Contains(view, "of input") {
+		t.Errorf("Expected the cache hit rate in the sidebar alone:\n%s", view)
+	}
+}
+
+// With copy.matchTemplate set, Enter resumes at the message of the first
+// listed match, following ] through the matches, and copies the plain
+// resume once the search is cleared
+func TestHarnessMatchResume(t *testing.T) {
+	h := newHarness(t, testSession{"aaaa", daysAgo(1), []string{
+		`{"type":"user","uuid":"u-1","message":{"role":"user","content":"first needle"}}`,
+		`{"type":"user","uuid":"u-2","message":{"role":"user","content":"second needle"}}`,
+	}})
+	clipboard := h.fakeClipboard()
+	h.m.config.Copy.MatchTemplate = "claude --resume {id} --at {message}"
+	h.m.matchPreviewCount = 1
+
+	h.keys("/", "needle", "enter", "enter")
+	if got := clipboard(); got != "claude --resume aaaa --at u-1" {
+		t.Errorf("Expected a resume at the first match, got %q (status %q)", got, h.m.statusMsg)
+	}
+	if view := h.m.View(); !strings.Contains(view, "--at u-1") {
+		t.Errorf("Expected the details to show the command Enter copies:\n%s", view)
+	}
+
+	h.keys("]", "enter")
+	if got := clipboard(); got != "claude --resume aaaa --at u-2" {
+		t.Errorf("Expected ] to move the resume point, got %q", got)
+	}
+
+	h.keys("esc", "enter")
+	if got := clipboard(); got != "claude --resume aaaa" {
+		t.Errorf("Expected the plain resume without a search, got %q", got)
+	}
+}
+
+// The status bar drops its less important hints as the terminal narrows,
+// staying on one line with the version in place
+func TestHarnessStatusBarFits(t *testing.T) {
+	h := newHarness(t, harnessSessions()...)
+	h.m.version = "v1.2.3"
+	for _, tc := range []struct {
+		width       int
+		shown, gone []string
+	}{
+		{120, []string{"[p] Projects", "[:] Commands", "[q] Quit"}, nil},
+		{80, []string{"[:] Commands", "[q] Quit"}, []string{"[p] Projects"}},
+		{50, []string{"[Enter] Copy", "[q] Quit"}, []string{"[:] Commands"}},
+	} {
+		h.send(tea.WindowSizeMsg{Width: tc.width, Height: 40})
+		bar := h.m.renderStatusBar()
+		if strings.Contains(bar, "\n") || lipgloss.Width(bar) != tc.width || !strings.Contains(bar, "v1.2.3") {
+			t.Errorf("Expected one %d-wide line ending in the version, got %q", tc.width, bar)
+		}
+		for _, hint := range tc.shown {
+			if !strings.Contains(bar, hint) {
+				t.Errorf("Expected %s at width %d, got %q", hint, tc.width, bar)
+			}
+		}
+		for _, hint := range tc.gone {
+			if strings.Contains(bar, hint) {
+				t.Errorf("Expected %s dropped at width %d, got %q", hint, tc.width, bar)
+			}
+		}
+	}
+
+	h.m.setStatus(strings.Repeat("a long status message ", 5))
+	if bar := h.m.renderStatusBar(); strings.Contains(bar, "\n") || !strings.Contains(bar, "v1.2.3") {
+		t.Errorf("Expected a long status cut to one line, got %q", bar)
+	}
+}
+
+// A merges every project's sessions into the list, each row naming its
+// project, and A again goes back to the detected project
+func TestHarnessAllProjects(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("CLAUDE_DIR", root)
+	other := filepath.Join(root, "-home-me-otherapp")
+	if err := os.Mkdir(other, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(other, "dddd.jsonl")
+	if err := os.WriteFile(path, []byte(userLine("elsewhere")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, daysAgo(4), daysAgo(4)); err != nil {
+		t.Fatal(err)
+	}
+	h := newHarness(t)
+	h.dir = filepath.Join(root, "-home-me-app")
+	if err := os.Mkdir(h.dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, session := range harnessSessions() {
+		h.writeSession(session)
+	}
+	h.start(h.dir)
+
+	h.keys("A")
+	if got := strings.Join(h.listed(), ","); got != "aaaa,bbbb,cccc,dddd" {
+		t.Fatalf("Expected every project's sessions, got %s", got)
+	}
+	if view := h.m.View(); !strings.Contains(view, "Sessions in all projects") || !strings.Contains(view, "otherapp") {
+		t.Errorf("Expected the rows to name their projects:\n%s", view)
+	}
+	if view, when := h.m.View(), getRelativeTime(h.m.filteredSessions[3].LastActive); !strings.Contains(view, "otherapp") || !strings.Contains(view, when) {
+		t.Errorf("Expected the ID rows to keep their time (%s) beside the project:\n%s", when, view)
+	}
+
+	h.keys("A")
+	if got := strings.Join(h.listed(), ","); got != "aaaa,bbbb,cccc" {
+		t.Errorf("Expected the detected project again, got %s", got)
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// idleCheckInterval is how often an idle quit is checked for, bounding how
+// late past the timeout it can happen
+const idleCheckInterval = 30 * time.Second
+
+// idleTickMsg asks the app whether it has been idle too long
+type idleTickMsg struct{}
+
+// scheduleIdleCheck waits for the next idle check. Nothing is scheduled
+// unless idle.quitAfterMinutes is set.
+func (m *Model) scheduleIdleCheck() tea.Cmd {
+	if m.idleTimeout <= 0 {
+		return nil
+	}
+	interval := idleCheckInterval
+	if m.idleTimeout < interval {
+		interval = m.idleTimeout
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return idleTickMsg{}
+	})
+}
+
+// handleIdleTick quits once no key has been pressed for the idle timeout,
+// stopping any search still running first so no ripgrep outlives the app
+func (m *Model) handleIdleTick() tea.Cmd {
+	if time.Since(m.lastInput) < m.idleTimeout {
+		return m.scheduleIdleCheck()
+	}
+	m.cancel()
+	return tea.Quit
+}
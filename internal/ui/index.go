@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// indexWarmedMsg reports that the search index read the listed sessions
+type indexWarmedMsg struct {
+	files int // files the index holds
+}
+
+// newSearchIndex returns the index content searches share, or nil when
+// search.index is off or ripgrep was asked for, which never reads it
+func newSearchIndex(enabled bool, backend search.Backend) *search.Index {
+	if !enabled || backend == search.BackendRipgrep {
+		return nil
+	}
+	return search.NewIndex()
+}
+
+// warmIndex reads the sessions into the search index in the background, so
+// the first search is as quick as the ones after it
+func (m *Model) warmIndex(sessions []model.SessionInfo) tea.Cmd {
+	if m.searchIndex == nil || len(sessions) == 0 {
+		return nil
+	}
+	index := m.searchIndex
+	ctx := m.ctx
+	return func() tea.Msg {
+		return indexWarmedMsg{files: index.Warm(ctx, sessions)}
+	}
+}
+
+// handleIndexWarmed notes the index size for debugging; there is nothing to
+// show the user
+func (m *Model) handleIndexWarmed(msg indexWarmedMsg) tea.Cmd {
+	log.Printf("search index holds %d files", msg.files)
+	return nil
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// jumpTimeout is how long a typed session number waits for another digit
+// or Enter before it is dropped
+const jumpTimeout = 2 * time.Second
+
+// jumpTimeoutMsg drops the typed session number unless more was typed since
+type jumpTimeoutMsg struct {
+	gen int
+}
+
+// typeJumpDigit adds a digit to the session number being typed in the list
+func (m *Model) typeJumpDigit(digit string) tea.Cmd {
+	if m.jumpBuffer == "" && digit == "0" {
+		return nil
+	}
+	m.jumpBuffer += digit
+	m.jumpGen++
+
+	gen := m.jumpGen
+	return tea.Batch(
+		m.setStatusFor(fmt.Sprintf("Go to session %s (Enter)", m.jumpBuffer), jumpTimeout),
+		tea.Tick(jumpTimeout, func(time.Time) tea.Msg {
+			return jumpTimeoutMsg{gen: gen}
+		}),
+	)
+}
+
+// handleJumpKey takes digits, Enter, Esc and Backspace while a session
+// number is being typed. It reports false for any other key, which drops
+// the number and is handled as usual.
+func (m *Model) handleJumpKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.jumpBuffer == "" {
+		return nil, false
+	}
+
+	switch msg.String() {
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
+		return m.typeJumpDigit(msg.String()), true
+	case "enter":
+		n, _ := strconv.Atoi(m.jumpBuffer)
+		m.jumpBuffer = ""
+		return m.jumpTo(n), true
+	case "esc":
+		m.jumpBuffer = ""
+		m.statusMsg = ""
+		return nil, true
+	case "backspace":
+		m.jumpBuffer = m.jumpBuffer[:len(m.jumpBuffer)-1]
+		if m.jumpBuffer == "" {
+			m.statusMsg = ""
+			return nil, true
+		}
+		return m.setStatusFor(fmt.Sprintf("Go to session %s (Enter)", m.jumpBuffer), jumpTimeout), true
+	}
+	m.jumpBuffer = ""
+	return nil, false
+}
+
+// jumpTo selects the n-th session of the list, counting from 1 and clamped
+// to the list, and loads it
+func (m *Model) jumpTo(n int) tea.Cmd {
+	if len(m.filteredSessions) == 0 {
+		return m.setStatus("No sessions")
+	}
+	if n < 1 {
+		n = 1
+	}
+	if n > len(m.filteredSessions) {
+		n = len(m.filteredSessions)
+	}
+
+	m.selected = n - 1
+	m.ensureVisible()
+	return tea.Batch(
+		m.setStatusFor(fmt.Sprintf("Session %d of %d", n, len(m.filteredSessions)), copyStatusDuration),
+		m.previewSelected(),
+	)
+}
@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+func newBenchModel(b *testing.B, count int) *Model {
+	b.Helper()
+	sessions := make([]model.SessionInfo, count)
+	for i := range sessions {
+		sessions[i] = model.SessionInfo{
+			ID:           fmt.Sprintf("%08d-0000-0000-0000-000000000000", i),
+			FilePath:     fmt.Sprintf("/tmp/%d.jsonl", i),
+			LastActive:   time.Now().Add(-time.Duration(i) * time.Minute),
+			Title:        fmt.Sprintf("Refactor the session list renderer, take %d, with a long title", i),
+			MessageCount: i,
+		}
+	}
+
+	m := NewApp(b.TempDir(), "bench", config.Default())
+	m.Update(tea.WindowSizeMsg{Width: 240, Height: 80})
+	m.Update(sessionsLoadedMsg{sessions: sessions, gen: m.loadGen})
+	m.listDisplay = ListDisplayTitle
+	return m
+}
+
+// Keys that leave the list untouched, such as typing into an overlay,
+// should reuse the rendered rows
+func BenchmarkViewUnchangedList(b *testing.B) {
+	m := newBenchModel(b, 5000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.View()
+	}
+}
+
+// Moving the selection re-renders the visible rows every frame
+func BenchmarkViewMovingSelection(b *testing.B) {
+	m := newBenchModel(b, 5000)
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("j")},
+		{Type: tea.KeyRunes, Runes: []rune("k")},
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Update(keys[i%2])
+		m.View()
+	}
+}
+
+// Wrapped rows take two lines, so fewer sessions fit and scrolling must
+// count lines rather than sessions
+func TestWrappedListScrollsByLines(t *testing.T) {
+	isolateConfig(t)
+	sessions := make([]model.SessionInfo, 12)
+	for i := range sessions {
+		sessions[i] = model.SessionInfo{
+			ID:         fmt.Sprint(i),
+			FilePath:   fmt.Sprintf("/tmp/%d.jsonl", i),
+			LastActive: time.Now().Add(-time.Duration(i) * time.Hour),
+			Title:      fmt.Sprintf("Session %d with a title far too long to fit beside its time", i),
+		}
+	}
+
+	cfg := config.Default()
+	cfg.List.Display = "title"
+	cfg.List.Wrap = true
+	m := NewApp(t.TempDir(), "test", cfg)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 16})
+	m.Update(sessionsLoadedMsg{sessions: sessions, gen: m.loadGen})
+
+	// 8 lines of rows hold 4 two-line sessions
+	innerWidth := m.listPaneWidth() - 4
+	if end := m.listVisibleEnd(0, 8, innerWidth); end != 4 {
+		t.Fatalf("Expected 4 sessions to fit, got %d", end)
+	}
+	for i := 0; i < 5; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if m.selected != 5 || m.scrollOffset != 2 {
+		t.Errorf("Expected session 5 on the last row (scroll 2), got selected %d scroll %d", m.selected, m.scrollOffset)
+	}
+	if max := m.maxListScroll(8, innerWidth); max != 8 {
+		t.Errorf("Expected the last 4 sessions at the bottom (scroll 8), got %d", max)
+	}
+}
+
+// Tiny terminals get a resize hint instead of panes with no room inside
+func TestViewTooSmall(t *testing.T) {
+	isolateConfig(t)
+	m := NewApp(t.TempDir(), "test", config.Default())
+	m.Update(sessionsLoadedMsg{sessions: []model.SessionInfo{{ID: "a", FilePath: "/tmp/a.jsonl"}}, gen: m.loadGen})
+
+	for _, size := range [][2]int{{120, 4}, {20, 40}, {1, 1}} {
+		m.Update(tea.WindowSizeMsg{Width: size[0], Height: size[1]})
+		view := m.View()
+		if size[1] > 1 && !strings.Contains(view, "Resize") {
+			t.Errorf("%dx%d: expected a resize hint, got %q", size[0], size[1], view)
+		}
+		if lines := strings.Count(view, "\n") + 1; lines > size[1] {
+			t.Errorf("%dx%d: expected at most %d lines, got %d", size[0], size[1], size[1], lines)
+		}
+	}
+}
+
+// X switches the details pane to the raw JSON in full and back to the
+// configured parsed view, which leaves the JSON out
+func TestRawViewToggle(t *testing.T) {
+	isolateConfig(t)
+	cfg := config.Default()
+	cfg.Details.View = "parsed"
+	m := NewApp(t.TempDir(), "test", cfg)
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m.Update(sessionsLoadedMsg{sessions: []model.SessionInfo{{ID: "a", FilePath: "/tmp/a.jsonl"}}, gen: m.loadGen})
+	long := strings.Repeat("y", 150)
+	m.Update(fullSessionLoadedMsg{filePath: "/tmp/a.jsonl", session: &model.FullSession{
+		ID:              "a",
+		FilePath:        "/tmp/a.jsonl",
+		LastRawMessages: []string{`{"type":"user","text":"` + long + `"}`},
+	}})
+
+	if strings.Contains(m.View(), `"type"`) {
+		t.Error("parsed view should leave out the raw JSON")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	view := m.View()
+	if !strings.Contains(view, `"type": "user"`) {
+		t.Error("raw view should pretty-print the JSON")
+	}
+	if strings.Count(view, "y") < len(long) {
+		t.Error("raw view should wrap long values rather than cut them")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	if m.detailsMode != DetailsParsed {
+		t.Errorf("expected X to return to the parsed view, got %v", m.detailsMode)
+	}
+}
+
+// The tree opens on the selected session with only its date expanded and
+// collapses back to the year
+func TestTreeGroupsByDate(t *testing.T) {
+	isolateConfig(t)
+	day := func(y int, mo time.Month, d int) time.Time { return time.Date(y, mo, d, 12, 0, 0, 0, time.Local) }
+	sessions := []model.SessionInfo{
+		{ID: "a", FilePath: "/tmp/a.jsonl", LastActive: day(2024, 5, 2)},
+		{ID: "b", FilePath: "/tmp/b.jsonl", LastActive: day(2024, 5, 1)},
+		{ID: "c", FilePath: "/tmp/c.jsonl", LastActive: day(2024, 5, 1)},
+		{ID: "d", FilePath: "/tmp/d.jsonl", LastActive: day(2023, 12, 31)},
+	}
+	m := NewApp(t.TempDir(), "test", config.Default())
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m.Update(sessionsLoadedMsg{sessions: sessions, gen: m.loadGen})
+	m.selected = 1
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+
+	labels := func() []string {
+		var out []string
+		for _, row := range m.treeRows() {
+			out = append(out, strings.TrimSpace(strings.Repeat(" ", row.depth)+row.label))
+		}
+		return out
+	}
+	want := "2024 May Thu 2 Wed 1 12:00  b 12:00  c 2023"
+	if got := strings.Join(labels(), " "); got != want {
+		t.Fatalf("Expected rows %q, got %q", want, got)
+	}
+	if row := m.treeRows()[m.treeSelected]; row.session != 1 {
+		t.Errorf("Expected the tree on session b, got %+v", row)
+	}
+
+	// Left goes up to the day, collapses it, then goes up to the month
+	for i := 0; i < 3; i++ {
+		m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	}
+	if got := strings.Join(labels(), " "); got != "2024 May Thu 2 Wed 1 2023" || m.treeRows()[m.treeSelected].label != "May" {
+		t.Errorf("Expected day collapsed and May selected, got %q at %d", got, m.treeSelected)
+	}
+}
+
+// d steps the list through today, the last week and the last month, then
+// back to every session
+func TestDatePresetCycle(t *testing.T) {
+	isolateConfig(t)
+	now := time.Now()
+	m := NewApp(t.TempDir(), "test", config.Default())
+	m.Update(sessionsLoadedMsg{sessions: []model.SessionInfo{
+		{ID: "today", FilePath: "/tmp/today.jsonl", LastActive: now},
+		{ID: "days", FilePath: "/tmp/days.jsonl", LastActive: now.AddDate(0, 0, -3)},
+		{ID: "weeks", FilePath: "/tmp/weeks.jsonl", LastActive: now.AddDate(0, 0, -20)},
+		{ID: "old", FilePath: "/tmp/old.jsonl", LastActive: now.AddDate(0, 0, -90)},
+	}, gen: m.loadGen})
+
+	for _, want := range []int{1, 2, 3, 4} {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+		if got := len(m.filteredSessions); got != want {
+			t.Errorf("%s: expected %d sessions, got %d", m.datePreset, want, got)
+		}
+	}
+}
+
+// A large transcript warns on the first Y and copies on the second
+func TestCopyLargeTranscriptAsksFirst(t *testing.T) {
+	isolateConfig(t)
+	m := NewApp(t.TempDir(), "test", config.Default())
+	msg := transcriptReadyMsg{filePath: "/tmp/a.jsonl", text: strings.Repeat("x", largeCopyBytes+1)}
+
+	m.handleTranscriptReady(msg)
+	if m.largeCopyPath != msg.filePath || !strings.Contains(m.statusMsg, "press Y again") {
+		t.Fatalf("Expected a warning before copying, got %q", m.statusMsg)
+	}
+	m.handleTranscriptReady(msg)
+	if m.largeCopyPath != "" {
+		t.Error("Expected the second Y to go ahead and copy")
+	}
+}
+
+// A message without a role still gets a heading in the transcript
+func TestTranscriptWithoutRole(t *testing.T) {
+	isolateConfig(t)
+	m := NewApp(t.TempDir(), "test", config.Default())
+	m.transcript = []model.Message{{Content: "orphan line"}}
+	lines, _ := m.transcriptLayout(40)
+	if !strings.Contains(strings.Join(lines, "\n"), "Unknown") {
+		t.Errorf("Expected an Unknown heading, got %q", lines)
+	}
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// listPaneWidth is the width of the session list pane, including its margin
+func (m *Model) listPaneWidth() int {
+	if m.width < 80 {
+		return m.width / 2
+	}
+	return 40
+}
+
+// rowDetails returns what a list row shows after the label: the relative
+// time with any size and count columns, and the match count or the copied,
+// active, note and unread indicators. unread is the session's count of new
+// messages.
+func (m *Model) rowDetails(session model.SessionInfo) (timeStr, matchIndicator string, unread int) {
+	// Format relative time, flagging sessions still being written
+	timeStr = getRelativeTime(session.LastActive)
+	if session.IsActive() {
+		timeStr = "● live"
+	}
+	if session.ID == m.currentID {
+		timeStr = "◆ current"
+	}
+	if m.showCount {
+		timeStr = fmt.Sprintf("%4d msgs  %s", session.MessageCount, timeStr)
+	}
+	if m.showSize {
+		timeStr = fmt.Sprintf("%8s  %s", formatSize(session.SizeBytes), timeStr)
+	}
+
+	// Add match indicator if searching, or the new message count of
+	// sessions that grew since they were last opened
+	unread = m.state.Unread(session.ID, session.MessageCount)
+	if unread > 0 {
+		matchIndicator = fmt.Sprintf(" +%d new", unread)
+	}
+	if _, ok := m.state.Notes[session.ID]; ok {
+		matchIndicator = " " + noteGlyph + matchIndicator
+	}
+	if m.activeSessions[session.ID] {
+		matchIndicator = " " + activeGlyph + matchIndicator
+	}
+	if session.FilePath == m.copiedPath {
+		matchIndicator = " " + copiedBadge + matchIndicator
+	}
+	if m.searchQuery != "" && !m.filterMode {
+		// Find match count for this session
+		for _, result := range m.searchResults {
+			if result.FilePath == session.FilePath {
+				matchIndicator = fmt.Sprintf(" [%d]", len(result.Matches))
+				break
+			}
+		}
+	}
+	return timeStr, matchIndicator, unread
+}
+
+// rowLabelWidth is the room a title or preview gets beside suffix
+func rowLabelWidth(suffix string, innerWidth int) int {
+	width := innerWidth - utf8.RuneCountInString(suffix)
+	if width < 10 {
+		width = 10
+	}
+	return width
+}
+
+// splitLabel breaks label after the last word that fits in width, or at
+// width when a single word is longer
+func splitLabel(label string, width int) (string, string) {
+	lines := wrapText(label, width)
+	if len(lines) > 1 && utf8.RuneCountInString(lines[0]) <= width {
+		return lines[0], strings.Join(lines[1:], " ")
+	}
+	runes := []rune(label)
+	return string(runes[:width]), strings.TrimSpace(string(runes[width:]))
+}
+
+// rowHeight is how many lines the i-th listed session takes: two when
+// wrapping and its label does not fit beside the suffix, else one
+func (m *Model) rowHeight(i, innerWidth int) int {
+	if !m.wrapList {
+		return 1
+	}
+	session := m.filteredSessions[i]
+	label := m.sessionLabel(session)
+	if label == "" {
+		return 1
+	}
+	timeStr, matchIndicator, _ := m.rowDetails(session)
+	if utf8.RuneCountInString(label) > rowLabelWidth(matchIndicator+" "+timeStr, innerWidth) {
+		return 2
+	}
+	return 1
+}
+
+// listVisibleEnd returns the end of the sessions that fit in height lines
+// from start. At least one is shown even if it does not fit.
+func (m *Model) listVisibleEnd(start, height, innerWidth int) int {
+	if !m.wrapList {
+		end := start + height
+		if end > len(m.filteredSessions) {
+			end = len(m.filteredSessions)
+		}
+		return end
+	}
+	used := 0
+	end := start
+	for end < len(m.filteredSessions) {
+		used += m.rowHeight(end, innerWidth)
+		if used > height && end > start {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// listScrollToShow returns the largest scroll offset that still shows the
+// session at index, putting it on the last row that fits
+func (m *Model) listScrollToShow(index, height, innerWidth int) int {
+	if !m.wrapList {
+		return index - height + 1
+	}
+	used := m.rowHeight(index, innerWidth)
+	start := index
+	for start > 0 {
+		next := used + m.rowHeight(start-1, innerWidth)
+		if next > height {
+			break
+		}
+		used = next
+		start--
+	}
+	return start
+}
+
+// maxListScroll is the scroll offset that shows the last sessions at the
+// bottom of the pane
+func (m *Model) maxListScroll(height, innerWidth int) int {
+	if len(m.filteredSessions) == 0 {
+		return 0
+	}
+	maxScroll := m.listScrollToShow(len(m.filteredSessions)-1, height, innerWidth)
+	if maxScroll < 0 {
+		return 0
+	}
+	return maxScroll
+}
+
+// toggleWrapList switches long titles and previews in the list between
+// truncated and wrapped onto a second line
+func (m *Model) toggleWrapList() tea.Cmd {
+	m.wrapList = !m.wrapList
+	m.invalidateList()
+	m.ensureVisible()
+	if m.wrapList {
+		return m.setStatus("Wrapping long titles onto a second line")
+	}
+	return m.setStatus("Truncating long titles")
+}
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// liveRefreshInterval is how often the selected session is checked for new
+// content while it is still being written
+const liveRefreshInterval = 2 * time.Second
+
+// liveTickMsg asks the app to check whether the watched session has grown
+type liveTickMsg struct {
+	filePath string
+}
+
+// scheduleLiveRefresh starts polling the loaded session if it is active.
+// Only one poll is kept in flight at a time.
+func (m *Model) scheduleLiveRefresh() tea.Cmd {
+	if m.liveTicking || m.fullSession == nil || m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+
+	session := m.filteredSessions[m.selected]
+	if session.FilePath != m.fullSession.FilePath || !session.IsActive() {
+		return nil
+	}
+
+	m.liveTicking = true
+	filePath := session.FilePath
+	return tea.Tick(liveRefreshInterval, func(time.Time) tea.Msg {
+		return liveTickMsg{filePath: filePath}
+	})
+}
+
+// handleLiveTick reloads the selected session when its file has changed
+// since the last poll and keeps polling while it stays active
+func (m *Model) handleLiveTick(msg liveTickMsg) tea.Cmd {
+	m.liveTicking = false
+
+	// Selection moved on; the next load schedules its own poll
+	if m.fullSession == nil || m.fullSession.FilePath != msg.filePath {
+		return nil
+	}
+
+	info, err := os.Stat(msg.filePath)
+	if err != nil {
+		return nil
+	}
+
+	changed := m.updateFileStat(msg.filePath, info)
+	if changed {
+		return m.loadFullSession(msg.filePath)
+	}
+	return m.scheduleLiveRefresh()
+}
+
+// updateFileStat records a new modification time and size for the session at
+// filePath in every list it appears in. It reports whether the time changed.
+func (m *Model) updateFileStat(filePath string, info os.FileInfo) bool {
+	changed := false
+	for _, list := range [][]model.SessionInfo{m.sessions, m.filteredSessions} {
+		for i := range list {
+			if list[i].FilePath == filePath && !list[i].LastActive.Equal(info.ModTime()) {
+				list[i].LastActive = info.ModTime()
+				list[i].SizeBytes = info.Size()
+				changed = true
+			}
+		}
+	}
+	if changed {
+		m.invalidateList()
+	}
+	return changed
+}
+
+// updateMessageCount replaces the listing scan's message count and cost
+// with those of a full parse, which may have seen a live session grow
+func (m *Model) updateMessageCount(session *model.FullSession) {
+	changed := false
+	for _, list := range [][]model.SessionInfo{m.sessions, m.filteredSessions} {
+		for i := range list {
+			if list[i].FilePath == session.FilePath && list[i].MessageCount != session.MessageCount {
+				list[i].MessageCount = session.MessageCount
+				list[i].CostUSD = session.TotalCostUSD
+				changed = true
+			}
+		}
+	}
+	if session.TotalCostUSD > 0 {
+		m.sessionsCost = true
+	}
+	if changed {
+		m.invalidateList()
+	}
+}
+
+// clockInterval is how often the list is redrawn so relative times such as
+// "3 minutes ago" do not go stale on an idle screen
+const clockInterval = 30 * time.Second
+
+// clockTickMsg redraws the list with fresh relative times
+type clockTickMsg struct{}
+
+// scheduleClockTick waits for the next redraw. Each tick schedules the next
+// one, so the chain ends with the program and nothing outlives a quit.
+func scheduleClockTick() tea.Cmd {
+	return tea.Tick(clockInterval, func(time.Time) tea.Msg {
+		return clockTickMsg{}
+	})
+}
+
+// refreshOnFocus reloads the session list when the terminal regains focus,
+// so sessions written while working elsewhere show up. It keeps the
+// selection and skips while searching or an overlay is open, where a reload
+// would throw away what is on screen.
+func (m *Model) refreshOnFocus() tea.Cmd {
+	if !m.focusRefresh || m.pickMode || m.refreshing || m.searchState != SearchStateNormal ||
+		m.showTranscript || m.showPicker || m.showPalette || m.showTable {
+		return nil
+	}
+	if m.selected < len(m.filteredSessions) {
+		m.pendingSelectPath = m.filteredSessions[m.selected].FilePath
+	}
+	m.scopeCache = make(map[SearchScope][]model.SessionInfo)
+	return m.loadSessions()
+}
+
+// toggleFocusRefresh turns refreshing on focus on or off for this run,
+// asking the terminal to start or stop reporting focus to match
+func (m *Model) toggleFocusRefresh() tea.Cmd {
+	m.focusRefresh = !m.focusRefresh
+	if m.focusRefresh {
+		return tea.Batch(tea.EnableReportFocus, m.setStatus("Refreshing the list when the terminal regains focus"))
+	}
+	return tea.Batch(tea.DisableReportFocus, m.setStatus("Refresh on focus off; press r to refresh"))
+}
+
+// rescanMsg carries a fresh read of one session file
+type rescanMsg struct {
+	info model.SessionInfo
+	full *model.FullSession
+	err  error
+}
+
+// rescanSelected re-reads the selected session's file, ignoring the
+// modification time the list and live polling go by. It catches files
+// changed behind a preserved timestamp, such as ones copied with cp -p.
+func (m *Model) rescanSelected() tea.Cmd {
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	filePath := m.filteredSessions[m.selected].FilePath
+	return func() tea.Msg {
+		info, err := m.parser.ScanSession(filePath)
+		if err != nil {
+			return rescanMsg{err: err}
+		}
+		full, err := m.parser.ParseFullSession(filePath)
+		return rescanMsg{info: info, full: full, err: err}
+	}
+}
+
+// handleRescan replaces the session's entry everywhere the list and search
+// scopes hold one, and its details if they are still on screen
+func (m *Model) handleRescan(msg rescanMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Error: %v", msg.err))
+	}
+
+	lists := [][]model.SessionInfo{m.sessions, m.filteredSessions}
+	for _, sessions := range m.scopeCache {
+		lists = append(lists, sessions)
+	}
+	for _, list := range lists {
+		for i := range list {
+			if list[i].FilePath == msg.info.FilePath {
+				// The project name is where the list found it, which the
+				// file path alone cannot always tell
+				msg.info.Project = list[i].Project
+				list[i] = msg.info
+			}
+		}
+	}
+	if msg.info.CostUSD > 0 {
+		m.sessionsCost = true
+	}
+	m.invalidateList()
+
+	if m.fullSession != nil && m.fullSession.FilePath == msg.full.FilePath {
+		m.fullSession = msg.full
+	}
+	return m.setStatusFor("Rescanned "+msg.info.ID, copyStatusDuration)
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/share"
+)
+
+// combinedExportMsg reports where the combined export of marked sessions
+// ended up
+type combinedExportMsg struct {
+	location string
+	size     int
+	count    int
+	err      error
+}
+
+// toggleMark marks or unmarks the selected session for a combined export
+// and moves on to the next one
+func (m *Model) toggleMark() tea.Cmd {
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	session := m.filteredSessions[m.selected]
+	if _, ok := m.marked[session.FilePath]; ok {
+		delete(m.marked, session.FilePath)
+	} else {
+		m.marked[session.FilePath] = session
+	}
+	m.invalidateList()
+
+	statusCmd := m.setStatus(fmt.Sprintf("%d marked", len(m.marked)))
+	if m.selected < len(m.filteredSessions)-1 {
+		m.selected++
+		m.ensureVisible()
+		return tea.Batch(statusCmd, m.previewSelected())
+	}
+	return statusCmd
+}
+
+// clearMarks unmarks every session
+func (m *Model) clearMarks() tea.Cmd {
+	m.marked = make(map[string]model.SessionInfo)
+	m.invalidateList()
+	return m.setStatus("Marks cleared")
+}
+
+// exportMarked writes every marked session into one Markdown document
+// through the share backend
+func (m *Model) exportMarked() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if len(m.marked) == 0 {
+		return m.setStatus("Mark sessions with Space first")
+	}
+
+	paths := make([]string, 0, len(m.marked))
+	for path := range m.marked {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	cfg := m.config.Share
+	statusCmd := m.setStatus(fmt.Sprintf("Exporting %d sessions...", len(paths)))
+
+	return tea.Batch(statusCmd, func() tea.Msg {
+		sessions := make([]model.FullSession, len(paths))
+		messages := make([][]model.Message, len(paths))
+		for i, path := range paths {
+			session, err := m.parser.ParseFullSession(path)
+			if err != nil {
+				return combinedExportMsg{err: err}
+			}
+			sessions[i] = *session
+			if messages[i], err = m.parser.ParseMessages(path); err != nil {
+				return combinedExportMsg{err: err}
+			}
+		}
+
+		content := export.CombinedMarkdown(sessions, messages)
+		filename := fmt.Sprintf("sessions-%s.md", time.Now().Format("20060102-150405"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		location, err := share.NewBackend(cfg).Share(ctx, filename, []byte(content))
+		return combinedExportMsg{location: location, size: len(content), count: len(paths), err: err}
+	})
+}
+
+func (m *Model) handleCombinedExport(msg combinedExportMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Export failed: %v", msg.err))
+	}
+	return m.setStatus(fmt.Sprintf("Exported %d sessions to %s (%s)", msg.count, msg.location, formatSize(int64(msg.size))))
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The smallest terminal the panes lay out in. Below it the bordered panes
+// have no room left inside, so a resize hint is shown instead.
+const (
+	minTerminalWidth  = 40
+	minTerminalHeight = 12
+)
+
+// tooSmall reports whether the terminal is known and below the minimum size
+func (m *Model) tooSmall() bool {
+	if m.width == 0 && m.height == 0 {
+		return false // no size reported yet
+	}
+	return m.width < minTerminalWidth || m.height < minTerminalHeight
+}
+
+// renderTooSmall asks for a bigger terminal in as few lines as fit
+func (m *Model) renderTooSmall() string {
+	lines := []string{
+		"Terminal too small",
+		fmt.Sprintf("Resize to at least %d×%d", minTerminalWidth, minTerminalHeight),
+		fmt.Sprintf("(now %d×%d)", m.width, m.height),
+	}
+	if len(lines) > m.height {
+		lines = lines[:m.height]
+	}
+	for i, line := range lines {
+		lines[i] = truncateRunes(line, m.width)
+	}
+	if len(lines) == 3 {
+		lines[2] = mutedTextStyle.Render(lines[2])
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		strings.Join(lines, "\n"))
+}
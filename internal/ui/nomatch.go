@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// longQueryRunes is the query length past which a shorter term is suggested
+const longQueryRunes = 20
+
+// showNoMatches reports whether the details pane should explain an empty
+// content search instead of showing a session
+func (m *Model) showNoMatches() bool {
+	return m.searchQuery != "" && !m.filterMode && m.searchState == SearchStateResults && len(m.filteredSessions) == 0
+}
+
+// noMatchHints suggests ways to widen a content search that found nothing,
+// the likeliest fix first
+func (m *Model) noMatchHints() []string {
+	var hints []string
+	query := m.searchQuery
+
+	// ripgrep reads the query as a regular expression and fails on an
+	// invalid one, which looks just like finding nothing. The built-in
+	// search and the index match such a query literally instead.
+	if _, err := regexp.Compile(query); err != nil {
+		if m.searchIndex == nil && search.UsesRipgrep(m.searchBackend) {
+			hints = append(hints, "The query is not a valid regular expression. Put \\ before special characters such as ( [ * + ?")
+		} else {
+			hints = append(hints, "The query is not a valid regular expression, so it was searched for as plain text. Check special characters such as ( [ * + ?")
+		}
+	}
+	if !m.ignoreCase {
+		hints = append(hints, "Ctrl+T ignores case")
+	}
+	if m.searchRecent > 0 && m.searchRecent < len(m.sessions) {
+		hints = append(hints, fmt.Sprintf("Ctrl+R searches every session, not only the latest %d", m.searchRecent))
+	}
+	if strings.Contains(strings.TrimSpace(query), " ") || len([]rune(query)) > longQueryRunes {
+		hints = append(hints, "A shorter term: the whole query must appear as written")
+	}
+	if m.effectiveScope() != SearchScopeAll {
+		hints = append(hints, "Ctrl+S widens the search to "+m.nextScopes())
+	}
+	return hints
+}
+
+// nextScopes names the scopes Ctrl+S cycles through from the current one
+func (m *Model) nextScopes() string {
+	if m.effectiveScope() == SearchScopeProject && m.startupDir != m.claudeDir {
+		return "the startup project, then all projects"
+	}
+	return "all projects"
+}
+
+// renderNoMatches fills the details pane after a content search that
+// matched no session
+func (m *Model) renderNoMatches(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	lines := []string{titleStyle.Render("No Matches"), ""}
+	lines = append(lines, wrapText(fmt.Sprintf("Nothing in %s contains '%s'.", m.effectiveScope(), m.searchQuery), innerWidth-2)...)
+	lines = append(lines, "")
+
+	if hints := m.noMatchHints(); len(hints) > 0 {
+		lines = append(lines, "Try:")
+		for _, hint := range hints {
+			for i, line := range wrapText(hint, innerWidth-4) {
+				prefix := "    "
+				if i == 0 {
+					prefix = "  • "
+				}
+				lines = append(lines, prefix+line)
+			}
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, mutedTextStyle.Render("Press / to edit the query or Esc to clear it"))
+
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	return detailsStyle.Width(width).Height(height).Render(strings.Join(lines, "\n"))
+}
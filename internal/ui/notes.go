@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noteGlyph flags sessions with a note in the list
+const noteGlyph = "✎"
+
+// newNoteInput builds the multi-line editor used for session notes
+func newNoteInput() textarea.Model {
+	input := textarea.New()
+	input.Placeholder = "Why does this session matter?"
+	input.CharLimit = 2000
+	input.ShowLineNumbers = false
+	return input
+}
+
+// openNoteEditor edits the selected session's note
+func (m *Model) openNoteEditor() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	session := m.filteredSessions[m.selected]
+	m.showNote = true
+	m.noteSession = session.ID
+	m.noteInput.SetValue(m.state.Notes[session.ID])
+	return m.noteInput.Focus()
+}
+
+func (m *Model) closeNoteEditor() {
+	m.showNote = false
+	m.noteInput.Blur()
+	m.noteSession = ""
+}
+
+// saveNote stores the edited note, removing it when left blank
+func (m *Model) saveNote() tea.Cmd {
+	sessionID := m.noteSession
+	note := m.noteInput.Value()
+	m.closeNoteEditor()
+	m.invalidateList()
+
+	if err := m.state.SetNote(sessionID, note); err != nil {
+		return m.setStatus(fmt.Sprintf("Could not save note: %v", err))
+	}
+	if _, ok := m.state.Notes[sessionID]; !ok {
+		return m.setStatus("Note removed")
+	}
+	return m.setStatus("Note saved")
+}
+
+func (m *Model) updateNote(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.closeNoteEditor()
+		return m, nil
+	case "ctrl+s":
+		return m, m.saveNote()
+	}
+
+	var cmd tea.Cmd
+	m.noteInput, cmd = m.noteInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) renderNote(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	title := "Note"
+	for _, session := range m.filteredSessions {
+		if session.ID == m.noteSession {
+			title = "Note: " + truncateRunes(tableLabel(session), innerWidth-6)
+			break
+		}
+	}
+	lines := []string{titleStyle.Render(title), ""}
+
+	inputHeight := innerHeight - len(lines)
+	if inputHeight < 1 {
+		inputHeight = 1
+	}
+	m.noteInput.SetWidth(innerWidth)
+	m.noteInput.SetHeight(inputHeight)
+	lines = append(lines, strings.Split(m.noteInput.View(), "\n")...)
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailsStyle.Width(width).Height(height).Render(content)
+}
+
+// noteLines wraps the selected session's note for the details pane
+func (m *Model) noteLines(width int) []string {
+	note, ok := m.state.Notes[m.fullSession.ID]
+	if !ok {
+		return nil
+	}
+	lines := []string{"Note:"}
+	for _, paragraph := range strings.Split(note, "\n") {
+		for _, line := range wrapText(paragraph, width) {
+			lines = append(lines, "  "+line)
+		}
+	}
+	return append(lines, "")
+}
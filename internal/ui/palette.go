@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// paletteCommand is one action offered by the command palette
+type paletteCommand struct {
+	name string
+	key  string // the direct keybinding, shown as a reminder
+	run  func(m *Model) tea.Cmd
+}
+
+// paletteCommands lists every action reachable from the palette. Add new
+// actions here as well as to the key handlers.
+var paletteCommands = []paletteCommand{
+	{"Search sessions", "/", func(m *Model) tea.Cmd { return m.enterSearchMode() }},
+	{"Filter sessions by name", "f", func(m *Model) tea.Cmd { return m.enterFilterMode() }},
+	{"Clear search", "esc", func(m *Model) tea.Cmd { return m.clearSearch() }},
+	{"Toggle case-sensitive search", "ctrl+t", func(m *Model) tea.Cmd { return m.toggleIgnoreCase() }},
+	{"Toggle searching only recent sessions", "ctrl+r", func(m *Model) tea.Cmd { return m.toggleSearchRecent() }},
+	{"Cycle search scope", "ctrl+s", func(m *Model) tea.Cmd { return m.cycleSearchScope() }},
+	{"Copy resume command", "enter", func(m *Model) tea.Cmd { return m.copyResumeCommand() }},
+	{"Copy resume command that compacts", "C", func(m *Model) tea.Cmd { return m.copyCompactResume() }},
+	{"Copy project path", "c", func(m *Model) tea.Cmd { return m.copyProjectPath() }},
+	{"Copy Markdown summary", "M", func(m *Model) tea.Cmd { return m.copyMarkdownSummary() }},
+	{"Copy link to session", "L", func(m *Model) tea.Cmd { return m.copySessionLink() }},
+	{"Copy last message JSON", "J", func(m *Model) tea.Cmd { return m.copyLastMessageJSON() }},
+	{"Show last raw message / last several", "x", func(m *Model) tea.Cmd { return m.toggleExpandRaw() }},
+	{"Switch details between parsed and raw JSON views", "X", func(m *Model) tea.Cmd { return m.toggleRawView() }},
+	{"Show/hide session position in details", "", func(m *Model) tea.Cmd { return m.togglePosition() }},
+	{"Edit session note", "N", func(m *Model) tea.Cmd { return m.openNoteEditor() }},
+	{"View transcript", "v", func(m *Model) tea.Cmd { return m.openTranscript() }},
+	{"Export / share session", "w", func(m *Model) tea.Cmd { return m.shareSession() }},
+	{"Pipe session to command", "|", func(m *Model) tea.Cmd { return m.pipeSession() }},
+	{"Copy plain-text transcript", "Y", func(m *Model) tea.Cmd { return m.copyTranscript() }},
+	{"Mark/unmark session", "space", func(m *Model) tea.Cmd { return m.toggleMark() }},
+	{"Export marked sessions as one Markdown file", "W", func(m *Model) tea.Cmd { return m.exportMarked() }},
+	{"Clear marks", "", func(m *Model) tea.Cmd { return m.clearMarks() }},
+	{"Sessions of this git repo / this project", "ctrl+g", func(m *Model) tea.Cmd { return m.toggleRepoOnly() }},
+	{"Sessions of all projects / this project", "A", func(m *Model) tea.Cmd { return m.toggleAllProjects() }},
+	{"Switch project", "p", func(m *Model) tea.Cmd { return m.openPicker() }},
+	{"All-projects table", "T", func(m *Model) tea.Cmd { return m.openTable() }},
+	{"Sessions by date tree / flat list", "D", func(m *Model) tea.Cmd { return m.toggleTree() }},
+	{"Cycle list label", "t", func(m *Model) tea.Cmd { return m.cycleListDisplay() }},
+	{"Cycle sort order", "o", func(m *Model) tea.Cmd { return m.cycleSort() }},
+	{"Hide/show sessions without messages", "e", func(m *Model) tea.Cmd { return m.toggleHideEmpty() }},
+	{"Show sessions active today", "", func(m *Model) tea.Cmd { return m.setDatePreset(DateToday) }},
+	{"Show sessions from the last 7 days", "", func(m *Model) tea.Cmd { return m.setDatePreset(DateWeek) }},
+	{"Show sessions from the last 30 days", "", func(m *Model) tea.Cmd { return m.setDatePreset(DateMonth) }},
+	{"Show sessions from all dates", "", func(m *Model) tea.Cmd { return m.setDatePreset(DateAll) }},
+	{"Toggle short session IDs", "", func(m *Model) tea.Cmd { return m.toggleShortIDs() }},
+	{"Toggle refresh on focus", "", func(m *Model) tea.Cmd { return m.toggleFocusRefresh() }},
+	{"Recently resumed sessions", "R", func(m *Model) tea.Cmd { return m.toggleResumed() }},
+	{"Show/hide file sizes", "s", func(m *Model) tea.Cmd { return m.toggleShowSize() }},
+	{"Show/hide message counts", "", func(m *Model) tea.Cmd { return m.toggleShowCount() }},
+	{"Wrap/truncate long titles", "", func(m *Model) tea.Cmd { return m.toggleWrapList() }},
+	{"List active sessions first / in sort order", "", func(m *Model) tea.Cmd { return m.toggleActiveFirst() }},
+	{"Toggle newline after copied resume command", "", func(m *Model) tea.Cmd { return m.toggleTrailingNewline() }},
+	{"Refresh sessions", "r", func(m *Model) tea.Cmd { return m.refresh() }},
+	{"Rescan selected session", "U", func(m *Model) tea.Cmd { return m.rescanSelected() }},
+	{"Quit", "q", func(m *Model) tea.Cmd { return tea.Quit }},
+}
+
+// openPalette shows the command palette with every command listed
+func (m *Model) openPalette() tea.Cmd {
+	m.showPalette = true
+	m.paletteInput.SetValue("")
+	m.paletteInput.Focus()
+	m.paletteSelected = 0
+	m.paletteScroll = 0
+	m.filterPalette()
+	return textinput.Blink
+}
+
+func (m *Model) closePalette() {
+	m.showPalette = false
+	m.paletteInput.Blur()
+	m.paletteResults = nil
+}
+
+// filterPalette narrows the palette to commands matching the typed query
+func (m *Model) filterPalette() {
+	names := make([]string, len(paletteCommands))
+	for i, command := range paletteCommands {
+		names[i] = command.name
+	}
+	m.paletteResults = m.paletteFilter.FilterText(m.paletteInput.Value(), names)
+
+	if m.paletteSelected >= len(m.paletteResults) {
+		m.paletteSelected = len(m.paletteResults) - 1
+	}
+	if m.paletteSelected < 0 {
+		m.paletteSelected = 0
+	}
+}
+
+func (m *Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.closePalette()
+		return m, nil
+	case "up", "ctrl+p", "ctrl+k":
+		if m.paletteSelected > 0 {
+			m.paletteSelected--
+		}
+		return m, nil
+	case "down", "ctrl+n", "ctrl+j":
+		if m.paletteSelected < len(m.paletteResults)-1 {
+			m.paletteSelected++
+		}
+		return m, nil
+	case "enter":
+		// A number goes to that session of the list, as typing it there does
+		if n, err := strconv.Atoi(strings.TrimSpace(m.paletteInput.Value())); err == nil {
+			m.closePalette()
+			return m, m.jumpTo(n)
+		}
+		if m.paletteSelected >= len(m.paletteResults) {
+			return m, nil
+		}
+		command := paletteCommands[m.paletteResults[m.paletteSelected].SessionIndex]
+		m.closePalette()
+		return m, command.run(m)
+	}
+
+	var cmd tea.Cmd
+	m.paletteInput, cmd = m.paletteInput.Update(msg)
+	m.paletteSelected = 0
+	m.paletteScroll = 0
+	m.filterPalette()
+	return m, cmd
+}
+
+func (m *Model) renderPalette(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	lines := []string{
+		titleStyle.Render(fmt.Sprintf("Commands (%d/%d)", len(m.paletteResults), len(paletteCommands))),
+		"",
+		"> " + m.paletteInput.View(),
+		"",
+	}
+
+	itemsHeight := innerHeight - len(lines)
+	if itemsHeight < 1 {
+		itemsHeight = 1
+	}
+
+	if m.paletteSelected < m.paletteScroll {
+		m.paletteScroll = m.paletteSelected
+	} else if m.paletteSelected >= m.paletteScroll+itemsHeight {
+		m.paletteScroll = m.paletteSelected - itemsHeight + 1
+	}
+
+	end := m.paletteScroll + itemsHeight
+	if end > len(m.paletteResults) {
+		end = len(m.paletteResults)
+	}
+
+	highlight := func(_ int, s string) string { return m.theme.termStyle(0).Render(s) }
+	for i := m.paletteScroll; i < end; i++ {
+		result := m.paletteResults[i]
+		command := paletteCommands[result.SessionIndex]
+
+		indices := make([]int, 0, len(result.Matches))
+		for _, match := range result.Matches {
+			indices = append(indices, match.StartOffset)
+		}
+
+		line := "  " + search.HighlightText(command.name, indices, highlight) +
+			mutedTextStyle.Render("  "+command.key)
+		if i == m.paletteSelected {
+			line = selectedItemStyle.Render(line)
+		} else {
+			line = sessionItemStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.paletteResults) == 0 {
+		lines = append(lines, mutedTextStyle.Render("  No commands match"))
+	}
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailsStyle.Width(width).Height(height).Render(content)
+}
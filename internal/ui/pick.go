@@ -0,0 +1,51 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// EnablePickMode turns the browser into a selection widget: Enter quits
+// with the selected session instead of copying its resume command, and
+// nothing is copied, exported or written to disk along the way
+func (m *Model) EnablePickMode() {
+	m.pickMode = true
+}
+
+// SetCurrentSession marks the session the browser was launched from and
+// selects it once the list loads. An ID not in the list is ignored.
+func (m *Model) SetCurrentSession(id string) {
+	m.currentID = id
+	m.pendingSelectID = id
+}
+
+// Picked returns the session chosen in pick mode, if any
+func (m *Model) Picked() (model.SessionInfo, bool) {
+	if m.picked == nil {
+		return model.SessionInfo{}, false
+	}
+	return *m.picked, true
+}
+
+// confirmSelected runs Enter on the list: pick the session in pick mode,
+// otherwise copy its resume command
+func (m *Model) confirmSelected() tea.Cmd {
+	if !m.pickMode {
+		return m.copyResumeCommand()
+	}
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	session := m.filteredSessions[m.selected]
+	m.picked = &session
+	return tea.Quit
+}
+
+// pickModeBlocked reports an action with side effects as unavailable in
+// pick mode, returning nil when the action may run
+func (m *Model) pickModeBlocked() tea.Cmd {
+	if !m.pickMode {
+		return nil
+	}
+	return m.setStatus("Not available in pick mode, press Enter to choose a session")
+}
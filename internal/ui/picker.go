@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// projectsLoadedMsg carries the project list for the picker
+type projectsLoadedMsg struct {
+	projects []model.ProjectInfo
+	excluded int // projects left out by projects.exclude
+	err      error
+}
+
+// rootDir returns the Claude projects root, which stays put while
+// claudeDir moves between projects
+func (m *Model) rootDir() string {
+	return m.root
+}
+
+// projectsRoot works out the Claude projects root. main exports it as
+// CLAUDE_DIR before narrowing claudeDir down to a single project.
+func projectsRoot(claudeDir string) string {
+	if dir := os.Getenv("CLAUDE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Dir(claudeDir)
+}
+
+// openPicker shows the project picker and starts loading projects
+func (m *Model) openPicker() tea.Cmd {
+	m.showPicker = true
+	m.pickerInput.SetValue("")
+	m.pickerInput.Focus()
+	m.pickerSelected = 0
+	m.pickerScroll = 0
+
+	rootDir := m.rootDir()
+	return tea.Batch(textinput.Blink, func() tea.Msg {
+		projects, err := m.parser.ListProjects(rootDir)
+		return projectsLoadedMsg{projects: projects, excluded: m.parser.ExcludedProjects(rootDir), err: err}
+	})
+}
+
+func (m *Model) closePicker() {
+	m.showPicker = false
+	m.pickerInput.Blur()
+	m.projects = nil
+	m.pickerResults = nil
+}
+
+// excludedNote mentions projects left out by projects.exclude in the
+// picker and table titles, and nothing when there are none
+func excludedNote(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" · %d excluded", count)
+}
+
+// projectDisplayPath is the human-readable form shown and matched in the picker
+func projectDisplayPath(project model.ProjectInfo) string {
+	return model.ShortenHome(project.DecodedPath())
+}
+
+// filterProjects narrows the picker to projects matching the typed query,
+// and to those under $HOME when that toggle is on
+func (m *Model) filterProjects() {
+	var paths []string
+	var indices []int // index into m.projects of each path
+	for i, project := range m.projects {
+		if m.homeOnly && !model.IsUnderHome(project.DecodedPath()) {
+			continue
+		}
+		paths = append(paths, projectDisplayPath(project))
+		indices = append(indices, i)
+	}
+	m.pickerResults = m.pickerFilter.FilterText(m.pickerInput.Value(), paths)
+	for i := range m.pickerResults {
+		m.pickerResults[i].SessionIndex = indices[m.pickerResults[i].SessionIndex]
+	}
+
+	if m.pickerSelected >= len(m.pickerResults) {
+		m.pickerSelected = len(m.pickerResults) - 1
+	}
+	if m.pickerSelected < 0 {
+		m.pickerSelected = 0
+	}
+}
+
+func (m *Model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.closePicker()
+		return m, nil
+	case "ctrl+o":
+		m.homeOnly = !m.homeOnly
+		m.pickerSelected = 0
+		m.pickerScroll = 0
+		m.filterProjects()
+		return m, nil
+	case "ctrl+s":
+		return m, m.cyclePickerSort()
+	case "up", "ctrl+p", "ctrl+k":
+		if m.pickerSelected > 0 {
+			m.pickerSelected--
+		}
+		return m, nil
+	case "down", "ctrl+n", "ctrl+j":
+		if m.pickerSelected < len(m.pickerResults)-1 {
+			m.pickerSelected++
+		}
+		return m, nil
+	case "enter":
+		if m.pickerSelected >= len(m.pickerResults) {
+			return m, nil
+		}
+		project := m.projects[m.pickerResults[m.pickerSelected].SessionIndex]
+		m.closePicker()
+
+		// Switch the session list over to the chosen project
+		m.claudeDir = project.Path
+		m.repoOnly = false
+		m.allProjects = false
+		m.resetSearch()
+		m.fullSession = nil
+		m.loading = true
+		return m, tea.Batch(m.setStatus(fmt.Sprintf("Switched to %s", projectDisplayPath(project))), m.loadSessions())
+	}
+
+	var cmd tea.Cmd
+	m.pickerInput, cmd = m.pickerInput.Update(msg)
+	m.pickerSelected = 0
+	m.pickerScroll = 0
+	m.filterProjects()
+	return m, cmd
+}
+
+func (m *Model) renderPicker(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	title := fmt.Sprintf("Projects (%d/%d)", len(m.pickerResults), len(m.projects))
+	if m.pickerSort != ProjectSortRecent {
+		title += " · by " + m.pickerSort.String()
+	}
+	if m.homeOnly {
+		title += " · home only"
+	}
+	title += excludedNote(m.excludedProjects)
+	lines := []string{
+		titleStyle.Render(title),
+		"",
+		"Filter: " + m.pickerInput.View(),
+		"",
+	}
+
+	itemsHeight := innerHeight - len(lines)
+	if itemsHeight < 1 {
+		itemsHeight = 1
+	}
+
+	// Keep the selection inside the visible window
+	if m.pickerSelected < m.pickerScroll {
+		m.pickerScroll = m.pickerSelected
+	} else if m.pickerSelected >= m.pickerScroll+itemsHeight {
+		m.pickerScroll = m.pickerSelected - itemsHeight + 1
+	}
+
+	end := m.pickerScroll + itemsHeight
+	if end > len(m.pickerResults) {
+		end = len(m.pickerResults)
+	}
+
+	highlight := func(_ int, s string) string { return m.theme.termStyle(0).Render(s) }
+	for i := m.pickerScroll; i < end; i++ {
+		result := m.pickerResults[i]
+		project := m.projects[result.SessionIndex]
+
+		marker := "  "
+		if project.Path == m.claudeDir {
+			marker = "• "
+		}
+		noun := "sessions"
+		if project.SessionCount == 1 {
+			noun = "session"
+		}
+		meta := fmt.Sprintf("  %d %s · %s", project.SessionCount, noun, getRelativeTime(project.LastActive))
+
+		// Truncate the plain path before highlighting so escape codes
+		// never get cut in half
+		path := projectDisplayPath(project)
+		maxPath := innerWidth - len(marker) - len(meta) - 2
+		if maxPath > ellipsisWidth() && utf8.RuneCountInString(path) > maxPath {
+			path = truncateRunes(path, maxPath)
+		}
+		var indices []int
+		for _, match := range result.Matches {
+			if match.StartOffset < len(path) {
+				indices = append(indices, match.StartOffset)
+			}
+		}
+
+		line := marker + search.HighlightText(path, indices, highlight) + mutedTextStyle.Render(meta)
+		if i == m.pickerSelected {
+			line = selectedItemStyle.Render(line)
+		} else {
+			line = sessionItemStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if len(m.projects) > 0 && len(m.pickerResults) == 0 {
+		lines = append(lines, mutedTextStyle.Render("  No projects match"))
+	}
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailsStyle.Width(width).Height(height).Render(content)
+}
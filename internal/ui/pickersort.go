@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// ProjectSort selects the order of the project picker
+type ProjectSort int
+
+const (
+	ProjectSortRecent   ProjectSort = iota // Most recently active first
+	ProjectSortPath                        // Alphabetical by decoded path
+	ProjectSortSessions                    // Most sessions first
+	projectSortCount
+)
+
+func (k ProjectSort) String() string {
+	switch k {
+	case ProjectSortPath:
+		return "path"
+	case ProjectSortSessions:
+		return "sessions"
+	}
+	return "recent"
+}
+
+// parseProjectSort maps a saved value to a ProjectSort, defaulting to
+// recency
+func parseProjectSort(value string) ProjectSort {
+	switch value {
+	case "path":
+		return ProjectSortPath
+	case "sessions":
+		return ProjectSortSessions
+	}
+	return ProjectSortRecent
+}
+
+// sortProjects orders projects by key, falling back to recency and then
+// path so the order never depends on the listing
+func sortProjects(projects []model.ProjectInfo, key ProjectSort) {
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		switch key {
+		case ProjectSortPath:
+			pa, pb := strings.ToLower(a.DecodedPath()), strings.ToLower(b.DecodedPath())
+			if pa != pb {
+				return pa < pb
+			}
+		case ProjectSortSessions:
+			if a.SessionCount != b.SessionCount {
+				return a.SessionCount > b.SessionCount
+			}
+		}
+		if !a.LastActive.Equal(b.LastActive) {
+			return a.LastActive.After(b.LastActive)
+		}
+		return a.DecodedPath() < b.DecodedPath()
+	})
+}
+
+// cyclePickerSort moves the picker on to the next order, keeping the
+// selected project selected, and remembers the choice for later runs
+func (m *Model) cyclePickerSort() tea.Cmd {
+	var selected string
+	if m.pickerSelected < len(m.pickerResults) {
+		selected = m.projects[m.pickerResults[m.pickerSelected].SessionIndex].Path
+	}
+
+	m.pickerSort = (m.pickerSort + 1) % projectSortCount
+	sortProjects(m.projects, m.pickerSort)
+	m.filterProjects()
+	for i, result := range m.pickerResults {
+		if m.projects[result.SessionIndex].Path == selected {
+			m.pickerSelected = i
+			break
+		}
+	}
+
+	if err := m.state.SetPickerSort(m.pickerSort.String()); err != nil {
+		return m.setStatus(fmt.Sprintf("Could not save project sort: %v", err))
+	}
+	return m.setStatus("Projects sorted by " + m.pickerSort.String())
+}
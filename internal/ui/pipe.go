@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+)
+
+// pipeReadyMsg carries the session content to feed the pipe command
+type pipeReadyMsg struct {
+	content string
+	err     error
+}
+
+// pipeDoneMsg reports how the pipe command exited
+type pipeDoneMsg struct {
+	name string
+	err  error
+}
+
+// pipeSession sends the selected session to the configured command on its
+// stdin, handing it the terminal until it exits
+func (m *Model) pipeSession() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+	if strings.TrimSpace(m.config.Pipe.Command) == "" {
+		return m.setStatus("No pipe command set (pipe.command or --pipe-command)")
+	}
+
+	filePath := m.fullSession.FilePath
+	raw := m.config.Pipe.Raw
+	return func() tea.Msg {
+		if raw {
+			data, err := os.ReadFile(filePath)
+			return pipeReadyMsg{content: string(data), err: err}
+		}
+		messages, err := m.parser.ParseMessages(filePath)
+		if err != nil {
+			return pipeReadyMsg{err: err}
+		}
+		return pipeReadyMsg{content: export.Text(messages)}
+	}
+}
+
+// handlePipeReady starts the pipe command once the content is read
+func (m *Model) handlePipeReady(msg pipeReadyMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Could not read session: %v", msg.err))
+	}
+
+	args := strings.Fields(m.config.Pipe.Command)
+	name := args[0]
+	if _, err := exec.LookPath(name); err != nil {
+		return m.setStatus(fmt.Sprintf("Pipe command not found: %s", name))
+	}
+
+	cmd := exec.Command(name, args[1:]...)
+	cmd.Stdin = strings.NewReader(msg.content)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return pipeDoneMsg{name: name, err: err}
+	})
+}
+
+func (m *Model) handlePipeDone(msg pipeDoneMsg) tea.Cmd {
+	var exitErr *exec.ExitError
+	if errors.As(msg.err, &exitErr) {
+		return m.setStatus(fmt.Sprintf("%s exited with status %d", msg.name, exitErr.ExitCode()))
+	}
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("%s failed: %v", msg.name, msg.err))
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+)
+
+// DetailsMode is what the details pane shows for a session
+type DetailsMode int
+
+const (
+	DetailsBoth   DetailsMode = iota // Metadata, summary and matches, then the raw JSON below
+	DetailsParsed                    // Metadata, summary and matches only
+	DetailsRaw                       // The raw JSON alone, pretty-printed in full and scrollable
+)
+
+// parseDetailsMode maps a config value to the details view X returns to,
+// defaulting to both
+func parseDetailsMode(value string) DetailsMode {
+	if value == "parsed" {
+		return DetailsParsed
+	}
+	return DetailsBoth
+}
+
+// toggleRawView switches the details pane between the raw JSON view and
+// the configured parsed view
+func (m *Model) toggleRawView() tea.Cmd {
+	if m.detailsMode == DetailsRaw {
+		m.detailsMode = m.detailsDefault
+		return m.setStatus("Showing session details")
+	}
+	m.detailsMode = DetailsRaw
+	m.rawScroll = 0
+	return m.setStatus("Showing raw JSON (PgUp/PgDn to scroll, X to go back)")
+}
+
+// scrollRaw moves the raw JSON view by a page, clamped when rendered. It
+// does nothing outside the raw view.
+func (m *Model) scrollRaw(pages int) {
+	if m.detailsMode != DetailsRaw {
+		return
+	}
+	page := m.height - 10
+	if page < 1 {
+		page = 1
+	}
+	m.rawScroll += pages * page
+	if m.rawScroll < 0 {
+		m.rawScroll = 0
+	}
+}
+
+// renderRawView fills the details pane with the session's last raw JSON
+// lines, pretty-printed and wrapped rather than cut, newest first. x
+// switches between the last line and the last details.rawMessages.
+func (m *Model) renderRawView(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 3 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	raw := m.fullSession.LastRawMessages
+	if !m.expandRaw && len(raw) > 1 {
+		raw = raw[:1]
+	}
+
+	var body []string
+	for i, rawMsg := range raw {
+		if len(raw) > 1 {
+			body = append(body, mutedTextStyle.Render(fmt.Sprintf("── %d ──", i+1)))
+		}
+		rawMsg = parser.ElideImages(rawMsg)
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, []byte(rawMsg), "", "  "); err != nil {
+			pretty.Reset()
+			pretty.WriteString(rawMsg)
+		}
+		for _, line := range strings.Split(pretty.String(), "\n") {
+			body = append(body, wrapRunes(line, innerWidth)...)
+		}
+	}
+
+	title := "Raw JSON"
+	if len(raw) > 1 {
+		title = fmt.Sprintf("Raw JSON: last %d messages, newest first", len(raw))
+	}
+	lines := []string{titleStyle.Render(title), ""}
+	room := innerHeight - len(lines)
+
+	// Clamp here, where the page size is known
+	maxScroll := len(body) - room
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.rawScroll > maxScroll {
+		m.rawScroll = maxScroll
+	}
+	end := m.rawScroll + room
+	if end > len(body) {
+		end = len(body)
+	}
+	lines = append(lines, body[m.rawScroll:end]...)
+	if len(raw) == 0 {
+		lines = append(lines, "No raw messages")
+	}
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	return detailsStyle.Width(width).Height(height).Render(strings.Join(lines, "\n"))
+}
+
+// wrapRunes breaks a line every width runes, keeping its spacing, so
+// indentation and long strings stay readable
+func wrapRunes(line string, width int) []string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return []string{line}
+	}
+	var lines []string
+	for len(runes) > width {
+		lines = append(lines, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(lines, string(runes))
+}
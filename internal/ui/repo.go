@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/gitrepo"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// SetRepo records the git repository the browser was started in, which
+// the repo view (ctrl+g) lists sessions of. Call it before the program
+// starts.
+func (m *Model) SetRepo(repo gitrepo.Repo) {
+	m.repo = repo
+	m.hasRepo = true
+	m.repoOnly = m.repoOnly || m.config.List.RepoOnly
+	if m.repoOnly {
+		m.allProjects = false
+	}
+}
+
+// repoSessions keeps the sessions recorded as run inside repo, in any of
+// its subdirectories or worktrees
+func repoSessions(sessions []model.SessionInfo, repo gitrepo.Repo) []model.SessionInfo {
+	matcher := gitrepo.NewMatcher(repo)
+	kept := sessions[:0]
+	for _, session := range sessions {
+		if matcher.Contains(session.Cwd) {
+			kept = append(kept, session)
+		}
+	}
+	return kept
+}
+
+// toggleRepoOnly switches the list between the browsed project and every
+// session run inside the startup git repository, whichever project
+// directory Claude filed it under
+func (m *Model) toggleRepoOnly() tea.Cmd {
+	if !m.hasRepo {
+		return m.setStatus("Not started inside a git repository")
+	}
+	m.repoOnly = !m.repoOnly
+	m.allProjects = false
+	if m.selected < len(m.filteredSessions) {
+		m.pendingSelectPath = m.filteredSessions[m.selected].FilePath
+	}
+	m.resetSearch()
+	m.scopeCache = make(map[SearchScope][]model.SessionInfo)
+	m.loading = true
+
+	status := "Showing this project's sessions"
+	if m.repoOnly {
+		status = fmt.Sprintf("Showing sessions run in %s", filepath.Base(m.repo.Root))
+	}
+	return tea.Batch(m.setStatus(status), m.loadSessions())
+}
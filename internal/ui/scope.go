@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// SearchScope selects which sessions a content search covers
+type SearchScope int
+
+const (
+	SearchScopeProject SearchScope = iota // the project being browsed
+	SearchScopeStartup                    // the project detected at startup
+	SearchScopeAll                        // every project under the root
+	searchScopeCount
+)
+
+func (s SearchScope) String() string {
+	switch s {
+	case SearchScopeStartup:
+		return "startup project"
+	case SearchScopeAll:
+		return "all projects"
+	}
+	return "this project"
+}
+
+// cycleSearchScope moves to the next scope and re-runs the current query
+func (m *Model) cycleSearchScope() tea.Cmd {
+	m.searchScope = (m.searchScope + 1) % searchScopeCount
+	statusCmd := m.setStatus("Searching " + m.searchScope.String())
+
+	if m.searchQuery == "" {
+		return statusCmd
+	}
+	return tea.Batch(statusCmd, m.performSearchCmd())
+}
+
+// effectiveScope folds the startup scope into the project scope while the
+// startup project is the one being browsed
+func (m *Model) effectiveScope() SearchScope {
+	if m.searchScope == SearchScopeStartup && m.startupDir == m.claudeDir {
+		return SearchScopeProject
+	}
+	return m.searchScope
+}
+
+// scopeLister returns a function listing the sessions of a scope outside
+// the browsed project, most recent first, or nil when they are already
+// known (the browsed project's list, or a cached one)
+func (m *Model) scopeLister(scope SearchScope) func() ([]model.SessionInfo, error) {
+	if _, ok := m.scopeCache[scope]; ok {
+		return nil
+	}
+	switch scope {
+	case SearchScopeStartup:
+		dir := m.startupDir
+		return func() ([]model.SessionInfo, error) {
+			return sortedByRecency(m.parser.ListSessions(dir))
+		}
+	case SearchScopeAll:
+		root := m.rootDir()
+		return func() ([]model.SessionInfo, error) {
+			return sortedByRecency(m.parser.ListAllSessions(root))
+		}
+	}
+	return nil
+}
+
+// sortedByRecency orders sessions most recently active first, as the
+// recent-sessions search limit expects
+func sortedByRecency(sessions []model.SessionInfo, err error) ([]model.SessionInfo, error) {
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastActive.After(sessions[j].LastActive)
+	})
+	return sessions, err
+}
@@ -0,0 +1,54 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// SearchEnter is what Enter does in the search box; Tab always just moves
+// to the results
+type SearchEnter int
+
+const (
+	SearchEnterResults SearchEnter = iota // Move to the results
+	SearchEnterCopy                       // Move to the results and copy the top one's resume command
+)
+
+// parseSearchEnter maps a config value to a SearchEnter, defaulting to
+// results
+func parseSearchEnter(value string) SearchEnter {
+	if value == "copy" {
+		return SearchEnterCopy
+	}
+	return SearchEnterResults
+}
+
+// copyTopResult selects the first result and copies its resume command.
+// Until the results for the query are in, or the top one's details have
+// loaded, it leaves m.copyTop set and is called again when they arrive.
+func (m *Model) copyTopResult() tea.Cmd {
+	if m.resultsQuery != m.searchQuery {
+		return nil
+	}
+	if len(m.filteredSessions) == 0 {
+		m.copyTop = false
+		return m.setStatus("No results to copy")
+	}
+	m.selected = 0
+	m.scrollOffset = 0
+	if !m.detailsCurrent() {
+		return m.loadFullSession(m.filteredSessions[0].FilePath)
+	}
+	m.copyTop = false
+	return m.copyResumeCommand()
+}
+
+// copyTopLoaded finishes a copyTopResult that was waiting on the top
+// result's details, unless the selection moved on in the meantime
+func (m *Model) copyTopLoaded() tea.Cmd {
+	if !m.copyTop {
+		return nil
+	}
+	m.copyTop = false
+	if m.selected != 0 || !m.detailsCurrent() {
+		return nil
+	}
+	return m.copyResumeCommand()
+}
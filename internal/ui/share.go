@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/export"
+	"github.com/davidpaquet/claude-session-browser/internal/share"
+)
+
+// shareCompleteMsg reports where an exported session ended up
+type shareCompleteMsg struct {
+	location string
+	backend  string
+	err      error
+}
+
+// shareSession exports the selected session through the configured share
+// backend in the background
+func (m *Model) shareSession() tea.Cmd {
+	if cmd := m.pickModeBlocked(); cmd != nil {
+		return cmd
+	}
+	if m.fullSession == nil {
+		return nil
+	}
+
+	session := m.fullSession
+	cfg := m.config.Share
+	statusCmd := m.setStatus("Exporting session...")
+
+	return tea.Batch(statusCmd, func() tea.Msg {
+		messages, err := m.parser.ParseMessages(session.FilePath)
+		if err != nil {
+			return shareCompleteMsg{err: err}
+		}
+
+		backend := share.NewBackend(cfg)
+		format := cfg.Format
+		if format == "" {
+			format = "html"
+			if backend.Name() == "gist" {
+				format = "markdown"
+			}
+		}
+
+		var content, filename string
+		if format == "markdown" {
+			content = export.Markdown(session, messages)
+			filename = session.ID + ".md"
+		} else {
+			content, err = export.HTML(session, messages)
+			if err != nil {
+				return shareCompleteMsg{err: err}
+			}
+			filename = session.ID + ".html"
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		location, err := backend.Share(ctx, filename, []byte(content))
+		return shareCompleteMsg{location: location, backend: backend.Name(), err: err}
+	})
+}
+
+func (m *Model) handleShareComplete(msg shareCompleteMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Export failed: %v", msg.err))
+	}
+
+	if err := m.clipboardMgr.Copy(msg.location); err != nil {
+		return m.setStatus(fmt.Sprintf("Exported to %s (copy failed: %v)", msg.location, err))
+	}
+	if msg.backend == "gist" {
+		return m.setStatus(fmt.Sprintf("Uploaded to %s (URL copied)", msg.location))
+	}
+	return m.setStatus(fmt.Sprintf("Exported to %s (path copied)", msg.location))
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// sidebarWidth is the width of the stats sidebar, including its margin
+const sidebarWidth = 46
+
+// showSidebar reports whether the terminal is wide enough for the stats
+// sidebar next to the details pane
+func (m *Model) showSidebar() bool {
+	return m.sidebarMinWidth > 0 && m.width > m.sidebarMinWidth
+}
+
+// renderSidebar shows the selected session's token counts, cost breakdown,
+// tags and every file it referenced. The details pane leaves out its own
+// token line and file list while the sidebar is shown.
+func (m *Model) renderSidebar(width, height int) string {
+	style := detailsStyle.MarginLeft(1)
+	// Account for border, padding, and margins as in renderDetails. Unlike
+	// the details pane, width covers the border and left margin too.
+	innerHeight := height - 5
+	innerWidth := width - 5
+
+	if innerHeight < 1 || innerWidth < 1 || m.fullSession == nil {
+		return style.Width(width - 3).Height(height).Render("")
+	}
+	session := m.fullSession
+
+	lines := []string{titleStyle.Render("Stats"), ""}
+
+	if session.Model != "" {
+		lines = append(lines, "Model:")
+		lines = append(lines, "  "+truncateRunes(session.Model, innerWidth-2))
+		lines = append(lines, "")
+	}
+
+	if tokens := session.Tokens; tokens.Total() > 0 {
+		lines = append(lines, "Tokens:")
+		lines = append(lines, statRow("Input", fmt.Sprint(tokens.Input)))
+		lines = append(lines, statRow("Output", fmt.Sprint(tokens.Output)))
+		lines = append(lines, statRow("Cache write", fmt.Sprint(tokens.CacheCreation)))
+		lines = append(lines, statRow("Cache read", fmt.Sprint(tokens.CacheRead)))
+		lines = append(lines, statRow("Total", fmt.Sprint(tokens.Total())))
+		if ratio, ok := tokens.CacheHitRatio(); ok {
+			lines = append(lines, statRow("Cache hits", fmt.Sprintf("%.0f%%", ratio*100)))
+		}
+		lines = append(lines, "")
+	}
+
+	if m.costDisplay.showsCost(m.sessionsCost) {
+		lines = append(lines, "Cost:")
+		lines = append(lines, statRow("Total", fmt.Sprintf("$%.4f", session.TotalCostUSD)))
+		if session.MessageCount > 0 {
+			lines = append(lines, statRow("Per message", fmt.Sprintf("$%.4f", session.TotalCostUSD/float64(session.MessageCount))))
+		}
+		if total := session.Tokens.Total(); total > 0 {
+			lines = append(lines, statRow("Per 1M tokens", fmt.Sprintf("$%.2f", session.TotalCostUSD/float64(total)*1e6)))
+		}
+		lines = append(lines, "")
+	}
+
+	if tags := m.sessionTags(session); len(tags) > 0 {
+		lines = append(lines, "Tags:")
+		for _, tag := range tags {
+			lines = append(lines, "  "+tag)
+		}
+		lines = append(lines, "")
+	}
+
+	// Files take whatever room is left
+	if files := session.ReferencedFiles; len(files) > 0 {
+		lines = append(lines, filesHeading(session))
+		room := innerHeight - len(lines)
+		for i, file := range files {
+			if i == room-1 && len(files) > room {
+				lines = append(lines, mutedTextStyle.Render(fmt.Sprintf("  +%d more", len(files)-i)))
+				break
+			}
+			lines = append(lines, "  "+truncateRunes(model.ShortenHome(file), innerWidth-2))
+		}
+	}
+
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+
+	return style.Width(width - 3).Height(height).Render(strings.Join(lines, "\n"))
+}
+
+// sessionTags lists what the browser knows about a session beyond its
+// contents: whether Claude Code names it as active, it is marked for
+// export, was resumed from here, or has a note
+func (m *Model) sessionTags(session *model.FullSession) []string {
+	var tags []string
+	if m.activeSessions[session.ID] {
+		tags = append(tags, "active")
+	}
+	if _, ok := m.marked[session.FilePath]; ok {
+		tags = append(tags, "marked")
+	}
+	if _, ok := m.state.Resumed[session.ID]; ok {
+		tags = append(tags, "resumed")
+	}
+	if _, ok := m.state.Notes[session.ID]; ok {
+		tags = append(tags, "note")
+	}
+	return tags
+}
+
+// statRow lines up a label and its value in the sidebar
+func statRow(label, value string) string {
+	return fmt.Sprintf("  %-14s %s", label, value)
+}
+
+// filesHeading counts every file the session touched, noting when only the
+// first of them were kept
+func filesHeading(session *model.FullSession) string {
+	if kept := len(session.ReferencedFiles); session.FileCount > kept {
+		return fmt.Sprintf("Files (%d, showing %d):", session.FileCount, kept)
+	}
+	return fmt.Sprintf("Files (%d):", len(session.ReferencedFiles))
+}
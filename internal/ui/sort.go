@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// SortKey selects the order of the session list
+type SortKey int
+
+const (
+	SortRecent   SortKey = iota // Most recently active first
+	SortTitle                   // Alphabetical by title, untitled last
+	SortCost                    // Most expensive first
+	SortMessages                // Most messages first
+	SortViewed                  // Most recently opened in the browser first
+	sortKeyCount
+)
+
+func (k SortKey) String() string {
+	switch k {
+	case SortTitle:
+		return "title"
+	case SortCost:
+		return "cost"
+	case SortMessages:
+		return "message count"
+	case SortViewed:
+		return "recently viewed"
+	}
+	return "most recent"
+}
+
+// parseSortKey maps a config value to a SortKey, defaulting to recency
+func parseSortKey(value string) SortKey {
+	switch value {
+	case "title":
+		return SortTitle
+	case "cost":
+		return SortCost
+	case "messages":
+		return SortMessages
+	case "viewed":
+		return SortViewed
+	}
+	return SortRecent
+}
+
+// compare orders two sessions by the key alone: negative when a comes
+// first, zero when the key cannot tell them apart. opened holds when each
+// session was last viewed; sessions never viewed come after the rest.
+func (k SortKey) compare(a, b model.SessionInfo, opened map[string]time.Time) int {
+	switch k {
+	case SortTitle:
+		if titleLess(a.Title, b.Title) {
+			return -1
+		}
+		if titleLess(b.Title, a.Title) {
+			return 1
+		}
+		return 0
+	case SortCost:
+		return cmp.Compare(b.CostUSD, a.CostUSD)
+	case SortMessages:
+		return cmp.Compare(b.MessageCount, a.MessageCount)
+	case SortViewed:
+		return opened[b.ID].Compare(opened[a.ID])
+	}
+	return b.LastActive.Compare(a.LastActive)
+}
+
+// sessionLess orders sessions by the primary key, then the secondary, then
+// ID, so sessions that tie on both keys still keep one order across
+// refreshes
+func sessionLess(a, b model.SessionInfo, primary, secondary SortKey, opened map[string]time.Time) bool {
+	if c := primary.compare(a, b, opened); c != 0 {
+		return c < 0
+	}
+	if c := secondary.compare(a, b, opened); c != 0 {
+		return c < 0
+	}
+	return a.ID < b.ID
+}
+
+// cycleSort rotates the list through the sort keys
+func (m *Model) cycleSort() tea.Cmd {
+	m.sortKey = (m.sortKey + 1) % sortKeyCount
+	m.refreshFiltered()
+	m.selected = 0
+	m.scrollOffset = 0
+
+	status := "Sorted by " + m.sortKey.String()
+	if m.thenSortKey != m.sortKey {
+		status += ", then " + m.thenSortKey.String()
+	}
+	return tea.Batch(m.setStatus(status), m.syncSelection())
+}
+
+// sortFiltered applies the sort keys to the filtered list. Recency then ID
+// is the order the list is built in, so only other keys do any work; they
+// sort a copy so the shared session slice keeps its order.
+func (m *Model) sortFiltered() {
+	if m.sortKey == SortRecent && m.thenSortKey == SortRecent {
+		return
+	}
+	sessions := append([]model.SessionInfo(nil), m.filteredSessions...)
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessionLess(sessions[i], sessions[j], m.sortKey, m.thenSortKey, m.state.Opened)
+	})
+	m.filteredSessions = sessions
+}
+
+// titleLess orders titles case-insensitively by code point, so the result
+// does not depend on the user's locale, with empty titles last
+func titleLess(a, b string) bool {
+	if a == "" || b == "" {
+		return a != "" && b == ""
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// Ties on the primary key fall to the secondary key, then to the ID
+func TestSessionLessSecondaryKey(t *testing.T) {
+	now := time.Now()
+	sessions := []model.SessionInfo{
+		{ID: "d", CostUSD: 0, LastActive: now.Add(-time.Hour)},
+		{ID: "c", CostUSD: 2, LastActive: now.Add(-2 * time.Hour)},
+		{ID: "b", CostUSD: 0, LastActive: now},
+		{ID: "a", CostUSD: 0, LastActive: now},
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessionLess(sessions[i], sessions[j], SortCost, SortRecent, nil)
+	})
+
+	want := []string{"c", "a", "b", "d"}
+	for i, id := range want {
+		if sessions[i].ID != id {
+			t.Fatalf("Expected order %v, got %+v", want, sessions)
+		}
+	}
+}
+
+// Active sessions lead in their sort order without reordering the list
+// they came from
+func TestActiveFirstKeepsOrder(t *testing.T) {
+	sessions := []model.SessionInfo{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}}
+	ordered := activeFirst(sessions, map[string]bool{"c": true, "b": true})
+
+	want := []string{"b", "c", "a", "d"}
+	for i, id := range want {
+		if ordered[i].ID != id {
+			t.Fatalf("Expected order %v, got %+v", want, ordered)
+		}
+	}
+	if sessions[1].ID != "b" || sessions[2].ID != "c" {
+		t.Errorf("Expected the original list untouched, got %+v", sessions)
+	}
+}
+
+// Projects sort by path or session count, falling back to recency
+func TestSortProjects(t *testing.T) {
+	now := time.Now()
+	projects := []model.ProjectInfo{
+		{Name: "-work-b", SessionCount: 2, LastActive: now},
+		{Name: "-work-a", SessionCount: 5, LastActive: now.Add(-time.Hour)},
+		{Name: "-work-c", SessionCount: 2, LastActive: now.Add(-2 * time.Hour)},
+	}
+	names := func() string {
+		var out []string
+		for _, project := range projects {
+			out = append(out, project.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	for key, want := range map[ProjectSort]string{
+		ProjectSortPath:     "-work-a,-work-b,-work-c",
+		ProjectSortSessions: "-work-a,-work-b,-work-c",
+		ProjectSortRecent:   "-work-b,-work-a,-work-c",
+	} {
+		sortProjects(projects, key)
+		if got := names(); got != want {
+			t.Errorf("%s: expected %s, got %s", key, want, got)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Durations for messages that should not use the configured default
+const (
+	copyStatusDuration    = 2 * time.Second
+	warningStatusDuration = 10 * time.Second
+)
+
+// setStatus shows msg in the status bar for the configured default duration
+func (m *Model) setStatus(msg string) tea.Cmd {
+	return m.setStatusFor(msg, m.statusDefault())
+}
+
+// setStatusFor shows msg for d. The returned command clears it when it
+// expires so the bar updates even if nothing else triggers a redraw.
+func (m *Model) setStatusFor(msg string, d time.Duration) tea.Cmd {
+	m.statusMsg = msg
+	m.statusTimer = time.Now()
+	m.statusDuration = d
+
+	timer := m.statusTimer
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return clearStatusMsg{timer: timer}
+	})
+}
+
+// statusDefault is the configured status duration, falling back to 3s
+func (m *Model) statusDefault() time.Duration {
+	if m.config.Status.DurationMs > 0 {
+		return time.Duration(m.config.Status.DurationMs) * time.Millisecond
+	}
+	return 3 * time.Second
+}
+
+// statusVisible reports whether the current status message has not expired
+func (m *Model) statusVisible() bool {
+	return m.statusMsg != "" && time.Since(m.statusTimer) < m.statusDuration
+}
+
+// hintLevel ranks a status bar key hint by how narrow the terminal can get
+// before it is dropped
+type hintLevel int
+
+const (
+	hintEssential hintLevel = iota // Always shown
+	hintCondensed                  // Dropped on narrow terminals
+	hintFull                       // Shown only when every hint fits
+)
+
+// keyHint is one "[key] Action" entry of the status bar
+type keyHint struct {
+	text  string
+	level hintLevel
+}
+
+// statusHints lists the key hints for what is on screen, in display order
+func (m *Model) statusHints() []keyHint {
+	switch {
+	case m.showTable:
+		return []keyHint{
+			{"[↑↓] Navigate", hintEssential},
+			{fmt.Sprintf("[1-%d] Sort by column", m.tableColumns()), hintCondensed},
+			{"[h] Home only", hintFull},
+			{"[Enter] Open", hintEssential},
+			{"[Esc] Close", hintEssential},
+		}
+	case m.showTree:
+		return []keyHint{
+			{"[↑↓] Navigate", hintEssential},
+			{"[←→] Collapse/expand", hintCondensed},
+			{"[Enter] Toggle or pick", hintEssential},
+			{"[Esc] Flat list", hintEssential},
+		}
+	case m.showNote:
+		return []keyHint{
+			{"[Ctrl+S] Save note", hintEssential},
+			{"[Esc] Cancel", hintEssential},
+			{"Leave it empty to remove the note", hintFull},
+		}
+	case m.showPalette:
+		return []keyHint{
+			{"[↑↓] Select", hintEssential},
+			{"[Enter] Run command", hintEssential},
+			{"[Esc] Cancel", hintEssential},
+			{"Type to filter...", hintFull},
+		}
+	case m.showPicker:
+		return []keyHint{
+			{"[↑↓] Select", hintEssential},
+			{"[Enter] Open project", hintEssential},
+			{"[Ctrl+O] Home only", hintCondensed},
+			{"[Ctrl+S] Sort", hintFull},
+			{"[Esc] Cancel", hintEssential},
+			{"Type to filter...", hintFull},
+		}
+	case m.showTranscript:
+		return []keyHint{
+			{"[↑↓] Scroll", hintEssential},
+			{"[PgUp/PgDn] Page", hintCondensed},
+			{"[n/N] Next/prev match", hintCondensed},
+			{"[Enter] Fold/unfold tools", hintFull},
+			{"[Esc] Close", hintEssential},
+		}
+	case m.searchState == SearchStateInput:
+		hints := []keyHint{{"[Tab/Enter] Results", hintEssential}}
+		if m.searchEnter == SearchEnterCopy && !m.pickMode {
+			hints = []keyHint{{"[Tab] Results", hintEssential}, {"[Enter] Copy top", hintEssential}}
+		}
+		if m.filterMode {
+			return append(hints,
+				keyHint{"[Esc] Cancel", hintEssential},
+				keyHint{"Type to filter sessions by title or ID", hintFull},
+			)
+		}
+		return append(hints,
+			keyHint{"[↑↓] History", hintCondensed},
+			keyHint{"[Ctrl+S] Scope", hintFull},
+			keyHint{"[Ctrl+T] Case", hintFull},
+			keyHint{"[Ctrl+R] Recent only", hintFull},
+			keyHint{"[Esc] Cancel", hintEssential},
+		)
+	case m.searchState == SearchStateResults:
+		enter := "Copy"
+		if m.pickMode {
+			enter = "Choose"
+		}
+		return []keyHint{
+			{"[↑↓] Navigate", hintEssential},
+			{"[v] View match", hintCondensed},
+			{"[+/-] More/fewer matches", hintFull},
+			{"[ [/] ] Scroll matches", hintFull},
+			{"[g/G] First/last", hintFull},
+			{"[/] Edit search", hintCondensed},
+			{"[Esc] Clear", hintEssential},
+			{"[Enter] " + enter, hintEssential},
+		}
+	case m.pickMode:
+		return []keyHint{
+			{"[↑↓] Navigate", hintEssential},
+			{"[Enter] Choose session", hintEssential},
+			{"[v] View", hintCondensed},
+			{"[/] Search", hintCondensed},
+			{"[p] Projects", hintFull},
+			{"[q] Cancel", hintEssential},
+		}
+	}
+	return []keyHint{
+		{"[↑↓] Navigate", hintEssential},
+		{"[Enter] Copy", hintEssential},
+		{"[v] View", hintFull},
+		{"[/] Search", hintCondensed},
+		{"[p] Projects", hintFull},
+		{"[:] Commands", hintCondensed},
+		{"[q] Quit", hintEssential},
+	}
+}
+
+// fitHints joins the most hints that fit in width: all of them, or those
+// kept on narrow terminals, or just the essential ones, cut short if even
+// those do not fit
+func fitHints(hints []keyHint, width int) string {
+	var text string
+	for level := hintFull; level >= hintEssential; level-- {
+		var kept []string
+		for _, hint := range hints {
+			if hint.level <= level {
+				kept = append(kept, hint.text)
+			}
+		}
+		text = strings.Join(kept, "  ")
+		if lipgloss.Width(text) <= width {
+			return text
+		}
+	}
+	return truncateRunes(text, width)
+}
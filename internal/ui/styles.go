@@ -1,6 +1,17 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// paneBorder outlines the panes, search bar and table; setPaneBorder
+// changes it
+var paneBorder = lipgloss.RoundedBorder()
+
+// ellipsis marks where text was cut; setEllipsis changes it
+var ellipsis = "..."
 
 var (
 	// Colors
@@ -29,9 +40,18 @@ var (
 		Foreground(lipgloss.Color("#FBBF24")).
 		Bold(true)
 
+	// Transcript message bodies, by role; the theme can recolor them
+	userTextStyle = lipgloss.NewStyle().
+		Foreground(secondaryColor)
+
+	assistantTextStyle = lipgloss.NewStyle()
+
+	toolTextStyle = lipgloss.NewStyle().
+		Foreground(mutedColor)
+
 	// List styles
 	sessionListStyle = lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(paneBorder).
 		BorderForeground(mutedColor).
 		Padding(1).
 		MarginTop(1).
@@ -40,6 +60,10 @@ var (
 	sessionItemStyle = lipgloss.NewStyle().
 		PaddingLeft(2)
 
+	unreadItemStyle = sessionItemStyle.
+		Foreground(secondaryColor).
+		Bold(true)
+
 	selectedItemStyle = lipgloss.NewStyle().
 		Background(selectedBg).
 		Foreground(primaryColor).
@@ -47,7 +71,7 @@ var (
 
 	// Details pane
 	detailsStyle = lipgloss.NewStyle().
-		BorderStyle(lipgloss.RoundedBorder()).
+		BorderStyle(paneBorder).
 		BorderForeground(mutedColor).
 		Padding(1).
 		MarginTop(1)
@@ -59,4 +83,41 @@ var (
 
 	keyHelpStyle = lipgloss.NewStyle().
 		Foreground(mutedColor)
-)
\ No newline at end of file
+)
+
+// parseBorder maps a config value to a border. "none" keeps an invisible
+// border so pane sizes do not change. Unknown names give rounded.
+func parseBorder(name string) lipgloss.Border {
+	switch name {
+	case "normal":
+		return lipgloss.NormalBorder()
+	case "thick":
+		return lipgloss.ThickBorder()
+	case "double":
+		return lipgloss.DoubleBorder()
+	case "none":
+		return lipgloss.HiddenBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// setEllipsis switches the truncation marker, keeping "..." when marker is
+// empty
+func setEllipsis(marker string) {
+	if marker == "" {
+		marker = "..."
+	}
+	ellipsis = marker
+}
+
+// ellipsisWidth is how many runes the truncation marker takes
+func ellipsisWidth() int {
+	return utf8.RuneCountInString(ellipsis)
+}
+
+// setPaneBorder switches every pane to border
+func setPaneBorder(border lipgloss.Border) {
+	paneBorder = border
+	sessionListStyle = sessionListStyle.BorderStyle(border)
+	detailsStyle = detailsStyle.BorderStyle(border)
+}
@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+)
+
+// TableColumn identifies a sortable column of the all-projects table
+type TableColumn int
+
+const (
+	TableColumnSession TableColumn = iota
+	TableColumnProject
+	TableColumnLastActive
+	TableColumnMessages
+	TableColumnCost
+)
+
+var tableHeaders = []string{"Session", "Project", "Last Active", "Msgs", "Cost"}
+
+// tableColumns is how many columns are shown, leaving off the cost column
+// when costs are hidden
+func (m *Model) tableColumns() int {
+	if m.costDisplay.showsCost(m.tableCost) {
+		return len(tableHeaders)
+	}
+	return len(tableHeaders) - 1
+}
+
+// tableLoadedMsg carries every session under the Claude root
+type tableLoadedMsg struct {
+	sessions []model.SessionInfo
+	excluded int // projects left out by projects.exclude
+	err      error
+}
+
+// openTable switches to the all-projects table and loads its rows
+func (m *Model) openTable() tea.Cmd {
+	m.showTable = true
+	m.tableLoading = true
+	m.tableSelected = 0
+	m.tableScroll = 0
+
+	rootDir := m.rootDir()
+	return func() tea.Msg {
+		sessions, err := m.parser.ListAllSessions(rootDir)
+		return tableLoadedMsg{sessions: sessions, excluded: m.parser.ExcludedProjects(rootDir), err: err}
+	}
+}
+
+// StartInTable opens the all-projects table as soon as the app starts, for
+// when no single project was chosen
+func (m *Model) StartInTable() {
+	m.startInTable = true
+}
+
+func (m *Model) closeTable() {
+	m.showTable = false
+	m.tableAll = nil
+	m.tableSessions = nil
+}
+
+// filterTable picks the rows to show from every loaded session, dropping
+// projects outside $HOME when that toggle is on
+func (m *Model) filterTable() {
+	if !m.homeOnly {
+		m.tableSessions = append([]model.SessionInfo(nil), m.tableAll...)
+	} else {
+		m.tableSessions = m.tableSessions[:0]
+		for _, session := range m.tableAll {
+			if model.IsUnderHome(model.DecodeProjectPath(session.Project)) {
+				m.tableSessions = append(m.tableSessions, session)
+			}
+		}
+	}
+	m.sortTable()
+	m.tableSelected = 0
+	m.tableScroll = 0
+}
+
+// sortTable orders the rows by the chosen column
+func (m *Model) sortTable() {
+	less := func(a, b model.SessionInfo) bool {
+		switch m.tableSort {
+		case TableColumnSession:
+			return strings.ToLower(tableLabel(a)) < strings.ToLower(tableLabel(b))
+		case TableColumnProject:
+			return a.Project < b.Project
+		case TableColumnMessages:
+			return a.MessageCount < b.MessageCount
+		case TableColumnCost:
+			return a.CostUSD < b.CostUSD
+		}
+		return a.LastActive.Before(b.LastActive)
+	}
+
+	sort.SliceStable(m.tableSessions, func(i, j int) bool {
+		if m.tableSortDesc {
+			return less(m.tableSessions[j], m.tableSessions[i])
+		}
+		return less(m.tableSessions[i], m.tableSessions[j])
+	})
+}
+
+// setTableSort sorts by column, flipping direction when it is already active
+func (m *Model) setTableSort(column TableColumn) {
+	if m.tableSort == column {
+		m.tableSortDesc = !m.tableSortDesc
+	} else {
+		m.tableSort = column
+		// Text columns read best ascending, numbers and dates descending
+		m.tableSortDesc = column != TableColumnSession && column != TableColumnProject
+	}
+	m.sortTable()
+	m.tableSelected = 0
+	m.tableScroll = 0
+}
+
+func (m *Model) updateTable(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.tableRowsHeight()
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "T":
+		m.closeTable()
+	case "up", "k":
+		m.tableSelected--
+	case "down", "j":
+		m.tableSelected++
+	case "pgup":
+		m.tableSelected -= page
+	case "pgdown":
+		m.tableSelected += page
+	case "g", "home":
+		m.tableSelected = 0
+	case "G", "end":
+		m.tableSelected = len(m.tableSessions) - 1
+	case "1", "2", "3", "4", "5":
+		if column := int(msg.String()[0] - '1'); column < m.tableColumns() {
+			m.setTableSort(TableColumn(column))
+		}
+	case "h":
+		m.homeOnly = !m.homeOnly
+		m.filterTable()
+	case "enter":
+		if m.tableSelected < len(m.tableSessions) {
+			return m, m.openInDetailView(m.tableSessions[m.tableSelected])
+		}
+	}
+
+	if m.tableSelected >= len(m.tableSessions) {
+		m.tableSelected = len(m.tableSessions) - 1
+	}
+	if m.tableSelected < 0 {
+		m.tableSelected = 0
+	}
+	return m, nil
+}
+
+// openInDetailView leaves the table for the two-pane view of the session's
+// project with that session selected
+func (m *Model) openInDetailView(session model.SessionInfo) tea.Cmd {
+	m.closeTable()
+	m.claudeDir = filepath.Dir(session.FilePath)
+	m.repoOnly = false
+	m.allProjects = false
+	m.pendingSelectPath = session.FilePath
+	m.resetSearch()
+	m.fullSession = nil
+	m.loading = true
+	return m.loadSessions()
+}
+
+// tableRowsHeight is the number of data rows that fit on screen
+func (m *Model) tableRowsHeight() int {
+	// Pane borders/padding (5), title and blank line (2), table borders
+	// and header (4), status bar (1)
+	rows := m.height - 12
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// tableLabel is the session column text: the title when known, else the ID
+func tableLabel(session model.SessionInfo) string {
+	if session.Title != "" {
+		return session.Title
+	}
+	return session.ID
+}
+
+func (m *Model) renderTable(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	title := fmt.Sprintf("All Sessions (%d)", len(m.tableSessions))
+	if m.homeOnly {
+		title += " · home only"
+	}
+	title += excludedNote(m.excludedProjects)
+	lines := []string{titleStyle.Render(title), ""}
+
+	if m.tableLoading {
+		lines = append(lines, "Loading sessions from all projects...")
+	} else {
+		rowsHeight := m.tableRowsHeight()
+		if m.tableSelected < m.tableScroll {
+			m.tableScroll = m.tableSelected
+		} else if m.tableSelected >= m.tableScroll+rowsHeight {
+			m.tableScroll = m.tableSelected - rowsHeight + 1
+		}
+		end := m.tableScroll + rowsHeight
+		if end > len(m.tableSessions) {
+			end = len(m.tableSessions)
+		}
+
+		columns := m.tableColumns()
+		headers := make([]string, columns)
+		for i, header := range tableHeaders[:columns] {
+			headers[i] = header
+			if TableColumn(i) == m.tableSort {
+				if m.tableSortDesc {
+					headers[i] += " ▼"
+				} else {
+					headers[i] += " ▲"
+				}
+			}
+			headers[i] = fmt.Sprintf("%d %s", i+1, headers[i])
+		}
+
+		rows := make([][]string, 0, end-m.tableScroll)
+		for _, session := range m.tableSessions[m.tableScroll:end] {
+			rows = append(rows, []string{
+				truncateRunes(tableLabel(session), 48),
+				truncateRunes(model.ShortenHome(model.DecodeProjectPath(session.Project)), 32),
+				session.LastActive.Local().Format("2006-01-02 15:04"),
+				fmt.Sprintf("%d", session.MessageCount),
+				fmt.Sprintf("$%.2f", session.CostUSD),
+			}[:columns])
+		}
+
+		selectedRow := m.tableSelected - m.tableScroll
+		t := table.New().
+			Border(paneBorder).
+			BorderStyle(lipgloss.NewStyle().Foreground(mutedColor)).
+			Headers(headers...).
+			Rows(rows...).
+			Width(innerWidth).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				style := lipgloss.NewStyle().Padding(0, 1)
+				switch {
+				case row == table.HeaderRow:
+					return style.Foreground(primaryColor).Bold(true)
+				case row == selectedRow:
+					return style.Background(selectedBg).Foreground(primaryColor)
+				}
+				return style
+			})
+
+		lines = append(lines, strings.Split(t.Render(), "\n")...)
+	}
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	if len(lines) > innerHeight {
+		lines = lines[:innerHeight]
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailsStyle.Width(width).Height(height).Render(content)
+}
+
+// handleTableLoaded fills the table once the all-projects scan finishes
+func (m *Model) handleTableLoaded(msg tableLoadedMsg) tea.Cmd {
+	m.tableLoading = false
+	var skipped *parser.SkippedError
+	if msg.err != nil && !errors.As(msg.err, &skipped) {
+		m.closeTable()
+		return m.setStatus(fmt.Sprintf("Error: %v", msg.err))
+	}
+	m.tableAll = msg.sessions
+	m.excludedProjects = msg.excluded
+	m.tableCost = anyCost(m.tableAll)
+	m.filterTable()
+	if skipped != nil {
+		return m.setStatusFor(skipped.Error(), warningStatusDuration)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"regexp"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+)
+
+// Theme holds the colors users can change. Everything else stays in the
+// fixed styles of styles.go.
+type Theme struct {
+	// Highlights style matches of each search term in turn, cycling when
+	// there are more terms than styles
+	Highlights []lipgloss.Style
+	// Roles overrides the term style for matches inside messages of a role
+	// ("user" or "assistant") in the transcript
+	Roles map[string]lipgloss.Style
+	// Text styles transcript message bodies by role, with "tool" for
+	// messages holding only tool calls or results
+	Text map[string]lipgloss.Style
+	// MatchCounts color the [N] match counts in the list, from the fewest
+	// matches to the most; empty leaves them plain
+	MatchCounts []lipgloss.Style
+}
+
+// newTheme builds the theme from config, keeping the stock highlight when
+// no colors are set
+func newTheme(cfg config.ThemeConfig) Theme {
+	theme := Theme{
+		Roles: make(map[string]lipgloss.Style),
+		Text: map[string]lipgloss.Style{
+			"user":      userTextStyle,
+			"assistant": assistantTextStyle,
+			"tool":      toolTextStyle,
+		},
+	}
+	for _, color := range cfg.Highlights {
+		if color != "" {
+			theme.Highlights = append(theme.Highlights, highlightColorStyle(color))
+		}
+	}
+	if len(theme.Highlights) == 0 {
+		theme.Highlights = []lipgloss.Style{highlightStyle}
+	}
+	if cfg.UserHighlight != "" {
+		theme.Roles["user"] = highlightColorStyle(cfg.UserHighlight)
+	}
+	if cfg.AssistantHighlight != "" {
+		theme.Roles["assistant"] = highlightColorStyle(cfg.AssistantHighlight)
+	}
+	for _, color := range cfg.MatchColors {
+		if color != "" {
+			theme.MatchCounts = append(theme.MatchCounts, lipgloss.NewStyle().Foreground(lipgloss.Color(color)))
+		}
+	}
+	for role, color := range map[string]string{"user": cfg.UserText, "assistant": cfg.AssistantText, "tool": cfg.ToolText} {
+		if color != "" {
+			theme.Text[role] = lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+		}
+	}
+	return theme
+}
+
+func highlightColorStyle(color string) lipgloss.Style {
+	return highlightStyle.Foreground(lipgloss.Color(color))
+}
+
+// matchCountStyle picks the style for a session with count matches when
+// the most any listed session has is most, spreading the scale evenly.
+// ok is false when match counts are left plain.
+func (t Theme) matchCountStyle(count, most int) (style lipgloss.Style, ok bool) {
+	if len(t.MatchCounts) == 0 || most <= 0 {
+		return style, false
+	}
+	level := (count*len(t.MatchCounts) - 1) / most
+	level = max(0, min(level, len(t.MatchCounts)-1))
+	return t.MatchCounts[level], true
+}
+
+// termStyle returns the style for matches of the term-th search term
+func (t Theme) termStyle(term int) lipgloss.Style {
+	return t.Highlights[term%len(t.Highlights)]
+}
+
+// matchStyle picks the style for a match of a term inside a message of
+// the given role; an empty role means the match is not in a message
+func (t Theme) matchStyle(term int, role string) lipgloss.Style {
+	if style, ok := t.Roles[role]; ok {
+		return style
+	}
+	return t.termStyle(term)
+}
+
+// textStyle returns the style for the body of a transcript message
+func (t Theme) textStyle(msg model.Message) lipgloss.Style {
+	if msg.Tool {
+		return t.Text["tool"]
+	}
+	return t.Text[msg.Role]
+}
+
+// highlightMessage styles a line of a transcript message: matches as in
+// highlightTerms and everything else in the message's text style
+func (t Theme) highlightMessage(text string, patterns []*regexp.Regexp, msg model.Message) string {
+	base := t.textStyle(msg)
+	return t.highlight(text, patterns, msg.Role, &base)
+}
+
+// highlightTerms styles every occurrence of each pattern in text, the
+// pattern's position choosing its style. Earlier patterns win overlaps.
+func (t Theme) highlightTerms(text string, patterns []*regexp.Regexp, role string) string {
+	return t.highlight(text, patterns, role, nil)
+}
+
+// highlight does the work of highlightTerms, rendering the text between
+// matches with base when it is set
+func (t Theme) highlight(text string, patterns []*regexp.Regexp, role string, base *lipgloss.Style) string {
+	plain := func(s string) string {
+		if base == nil {
+			return s
+		}
+		return base.Render(s)
+	}
+	if len(patterns) == 0 {
+		return plain(text)
+	}
+
+	// Find which term, if any, covers each byte
+	owner := make([]int, len(text))
+	for i := range owner {
+		owner[i] = -1
+	}
+	for term, pattern := range patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			for i := loc[0]; i < loc[1]; i++ {
+				if owner[i] < 0 {
+					owner[i] = term
+				}
+			}
+		}
+	}
+
+	var out []byte
+	for start := 0; start < len(text); {
+		end := start + 1
+		for end < len(text) && owner[end] == owner[start] {
+			end++
+		}
+		if owner[start] < 0 {
+			out = append(out, plain(text[start:end])...)
+		} else {
+			out = append(out, t.matchStyle(owner[start], role).Render(text[start:end])...)
+		}
+		start = end
+	}
+	return string(out)
+}
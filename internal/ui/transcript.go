@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// transcriptLoadedMsg carries the parsed turns for the transcript viewer
+type transcriptLoadedMsg struct {
+	messages  []model.Message
+	focusLine int // JSONL line number to scroll to, 0 for the top
+	err       error
+}
+
+// openTranscript opens the viewer for the selected session. When a search is
+// active the viewer starts on the first match of that session.
+func (m *Model) openTranscript() tea.Cmd {
+	if m.fullSession == nil {
+		return nil
+	}
+
+	focusLine := 0
+	m.transcriptMatch = 0
+	if matches := m.currentMatches(); len(matches) > 0 {
+		focusLine = matches[0].LineNumber
+	}
+
+	return m.loadTranscript(m.fullSession.FilePath, focusLine)
+}
+
+func (m *Model) loadTranscript(filePath string, focusLine int) tea.Cmd {
+	return func() tea.Msg {
+		messages, err := m.parser.ParseMessages(filePath)
+		return transcriptLoadedMsg{messages: messages, focusLine: focusLine, err: err}
+	}
+}
+
+// queryPattern matches the search query the way the search did, as a
+// regexp with the search's case sensitivity
+func (m *Model) queryPattern() *regexp.Regexp {
+	return search.CompileQuery(m.searchQuery, m.searchOptions())
+}
+
+// currentMatches returns the search matches of the selected session
+func (m *Model) currentMatches() []search.Match {
+	if m.searchQuery == "" || m.filterMode || m.fullSession == nil {
+		return nil
+	}
+	for _, result := range m.searchResults {
+		if result.FilePath == m.fullSession.FilePath {
+			return result.Matches
+		}
+	}
+	return nil
+}
+
+// transcriptFold is a run of consecutive tool-only messages shown as one
+// foldable line
+type transcriptFold struct {
+	start, end int // message range, end exclusive
+	line       int // layout line of the fold header
+}
+
+// toolRunStart returns the first message of the tool run holding message i
+func (m *Model) toolRunStart(i int) int {
+	for i > 0 && m.transcript[i-1].Tool {
+		i--
+	}
+	return i
+}
+
+// toggleVisibleFold folds or unfolds the topmost tool run on screen
+func (m *Model) toggleVisibleFold(page int) {
+	for _, fold := range m.transcriptFolds {
+		if fold.line >= m.transcriptScroll && fold.line < m.transcriptScroll+page {
+			m.transcriptExpanded[fold.start] = !m.transcriptExpanded[fold.start]
+			m.transcriptCache = nil
+			return
+		}
+	}
+}
+
+// focusTranscriptLine anchors the viewer on the message containing the given
+// JSONL line, keeping the preceding turn visible for context
+func (m *Model) focusTranscriptLine(line int) {
+	m.transcriptFocus = -1
+	for i, msg := range m.transcript {
+		if msg.LineNumber > line {
+			break
+		}
+		m.transcriptFocus = i
+	}
+
+	// Never leave a match hidden inside a folded tool run
+	if m.config.Transcript.CollapseTools && m.transcriptFocus >= 0 && m.transcript[m.transcriptFocus].Tool {
+		m.transcriptExpanded[m.toolRunStart(m.transcriptFocus)] = true
+	}
+
+	m.transcriptAnchor = m.transcriptFocus - 1
+	if m.transcriptAnchor < 0 {
+		m.transcriptAnchor = 0
+	}
+	m.transcriptCache = nil // focus marker is part of the layout
+}
+
+func (m *Model) updateTranscript(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	page := m.height - 6
+	if page < 1 {
+		page = 1
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "v":
+		m.showTranscript = false
+		m.transcript = nil
+		m.transcriptCache = nil
+	case "up", "k":
+		m.transcriptScroll--
+	case "down", "j":
+		m.transcriptScroll++
+	case "pgup", "b":
+		m.transcriptScroll -= page
+	case "pgdown", " ", "f":
+		m.transcriptScroll += page
+	case "g", "home":
+		m.transcriptScroll = 0
+	case "G", "end":
+		m.transcriptScroll = len(m.transcriptCache)
+	case "enter":
+		m.toggleVisibleFold(page)
+	case "n", "N":
+		matches := m.currentMatches()
+		if len(matches) == 0 {
+			return m, nil
+		}
+		if msg.String() == "n" {
+			m.transcriptMatch = (m.transcriptMatch + 1) % len(matches)
+		} else {
+			m.transcriptMatch = (m.transcriptMatch - 1 + len(matches)) % len(matches)
+		}
+		m.focusTranscriptLine(matches[m.transcriptMatch].LineNumber)
+	}
+
+	return m, nil
+}
+
+// transcriptLayout renders every message into lines for the given width and
+// records the first line of each message so the viewer can scroll to it
+func (m *Model) transcriptLayout(width int) ([]string, []int) {
+	if m.transcriptCache != nil && m.transcriptCacheWidth == width {
+		return m.transcriptCache, m.transcriptStarts
+	}
+
+	var termPatterns []*regexp.Regexp
+	if m.searchQuery != "" && !m.filterMode {
+		termPatterns = append(termPatterns, m.queryPattern())
+	}
+
+	var lines []string
+	starts := make([]int, len(m.transcript))
+	appendMessage := func(i int) {
+		msg := m.transcript[i]
+		starts[i] = len(lines)
+
+		header := msg.RoleTitle()
+		if !msg.Timestamp.IsZero() {
+			header += " · " + msg.Timestamp.Local().Format("2006-01-02 15:04")
+		}
+		if i == m.transcriptFocus {
+			lines = append(lines, highlightStyle.Render("▶ "+header))
+		} else {
+			lines = append(lines, titleStyle.Render("  "+header))
+		}
+
+		for _, paragraph := range strings.Split(msg.Content, "\n") {
+			wrapped := wrapText(paragraph, width-2)
+			if len(wrapped) == 0 {
+				lines = append(lines, "")
+				continue
+			}
+			for _, line := range wrapped {
+				lines = append(lines, "  "+m.theme.highlightMessage(line, termPatterns, msg))
+			}
+		}
+		lines = append(lines, "")
+	}
+
+	m.transcriptFolds = nil
+	for i := 0; i < len(m.transcript); {
+		if !m.config.Transcript.CollapseTools || !m.transcript[i].Tool {
+			appendMessage(i)
+			i++
+			continue
+		}
+
+		fold := transcriptFold{start: i, end: i, line: len(lines)}
+		for fold.end < len(m.transcript) && m.transcript[fold.end].Tool {
+			fold.end++
+		}
+		m.transcriptFolds = append(m.transcriptFolds, fold)
+
+		label := fmt.Sprintf("%d tool messages", fold.end-fold.start)
+		if fold.end-fold.start == 1 {
+			label = "1 tool message"
+		}
+		if m.transcriptExpanded[fold.start] {
+			lines = append(lines, mutedTextStyle.Render("▾ "+label))
+			for k := fold.start; k < fold.end; k++ {
+				appendMessage(k)
+			}
+		} else {
+			lines = append(lines, mutedTextStyle.Render("▸ "+label+" (Enter to expand)"), "")
+			for k := fold.start; k < fold.end; k++ {
+				starts[k] = fold.line
+			}
+		}
+		i = fold.end
+	}
+
+	m.transcriptCache = lines
+	m.transcriptStarts = starts
+	m.transcriptCacheWidth = width
+	return lines, starts
+}
+
+func (m *Model) renderTranscript(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return detailsStyle.Width(width).Height(height).Render("")
+	}
+
+	title := "Transcript"
+	if m.fullSession != nil {
+		title = fmt.Sprintf("Transcript: %s", m.fullSession.ID)
+	}
+	if matches := m.currentMatches(); len(matches) > 0 {
+		title += fmt.Sprintf(" (match %d/%d)", m.transcriptMatch+1, len(matches))
+	}
+
+	body, starts := m.transcriptLayout(innerWidth)
+	bodyHeight := innerHeight - 2
+
+	// Apply a pending anchor now that line positions are known
+	if m.transcriptAnchor >= 0 && m.transcriptAnchor < len(starts) {
+		m.transcriptScroll = starts[m.transcriptAnchor]
+		m.transcriptAnchor = -1
+	}
+
+	maxScroll := len(body) - bodyHeight
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.transcriptScroll > maxScroll {
+		m.transcriptScroll = maxScroll
+	}
+	if m.transcriptScroll < 0 {
+		m.transcriptScroll = 0
+	}
+
+	lines := []string{titleStyle.Render(title), ""}
+	if len(body) == 0 {
+		lines = append(lines, mutedTextStyle.Render("No messages in this session"))
+	}
+	end := m.transcriptScroll + bodyHeight
+	if end > len(body) {
+		end = len(body)
+	}
+	lines = append(lines, body[m.transcriptScroll:end]...)
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+
+	content := strings.Join(lines, "\n")
+	return detailsStyle.Width(width).Height(height).Render(content)
+}
@@ -0,0 +1,256 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// treeRow is one visible line of the date tree: a year, month or day node,
+// or a session under an expanded day
+type treeRow struct {
+	key     string // node key such as "2024-05-01", or empty for a session
+	label   string
+	depth   int
+	count   int // sessions under a node
+	session int // index into filteredSessions, or -1 for a node
+}
+
+// treeKeys returns the year, month and day node keys a time falls under
+func treeKeys(t time.Time) [3]string {
+	t = t.Local()
+	return [3]string{t.Format("2006"), t.Format("2006-01"), t.Format("2006-01-02")}
+}
+
+// treeLabels names the year, month and day nodes of a time
+func treeLabels(t time.Time) [3]string {
+	t = t.Local()
+	return [3]string{t.Format("2006"), t.Format("January"), t.Format("Mon 2")}
+}
+
+// treeRows lays out the listed sessions under year, month and day nodes,
+// newest first, leaving out the children of collapsed nodes
+func (m *Model) treeRows() []treeRow {
+	order := make([]int, len(m.filteredSessions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return m.filteredSessions[order[a]].LastActive.After(m.filteredSessions[order[b]].LastActive)
+	})
+
+	counts := make(map[string]int)
+	for _, i := range order {
+		for _, key := range treeKeys(m.filteredSessions[i].LastActive) {
+			counts[key]++
+		}
+	}
+
+	var rows []treeRow
+	var open [3]string // the node keys of the previous session
+	for _, i := range order {
+		session := m.filteredSessions[i]
+		keys := treeKeys(session.LastActive)
+		labels := treeLabels(session.LastActive)
+
+		// A node is shown when its parent is expanded, once per run of
+		// sessions under it
+		visible := true
+		for depth, key := range keys {
+			if !visible {
+				break
+			}
+			if key != open[depth] {
+				rows = append(rows, treeRow{key: key, label: labels[depth], depth: depth, count: counts[key], session: -1})
+			}
+			visible = m.treeExpanded[key]
+		}
+		open = keys
+		if visible {
+			rows = append(rows, treeRow{label: m.treeSessionLabel(i), depth: 3, session: i})
+		}
+	}
+	return rows
+}
+
+// treeSessionLabel is a session's row in the tree: its time of day and
+// label, falling back to the short ID
+func (m *Model) treeSessionLabel(i int) string {
+	session := m.filteredSessions[i]
+	label := m.sessionLabel(session)
+	if label == "" {
+		label = session.ShortID()
+	}
+	return session.LastActive.Local().Format("15:04") + "  " + label
+}
+
+// toggleTree switches the session list between the flat list and the date
+// tree, opening the tree on the selected session
+func (m *Model) toggleTree() tea.Cmd {
+	m.showTree = !m.showTree
+	if !m.showTree {
+		m.ensureVisible()
+		return nil
+	}
+
+	m.treeSelected = 0
+	m.treeScroll = 0
+	if m.selected >= len(m.filteredSessions) {
+		return nil
+	}
+	for _, key := range treeKeys(m.filteredSessions[m.selected].LastActive) {
+		m.treeExpanded[key] = true
+	}
+	for i, row := range m.treeRows() {
+		if row.session == m.selected {
+			m.treeSelected = i
+			break
+		}
+	}
+	return nil
+}
+
+// updateTree handles keys while the date tree replaces the list. Moving
+// onto a session previews it like moving through the list.
+func (m *Model) updateTree(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.treeRows()
+	if len(rows) == 0 {
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "esc", "q", "D":
+			m.showTree = false
+		}
+		return m, nil
+	}
+	if m.treeSelected >= len(rows) {
+		m.treeSelected = len(rows) - 1
+	}
+	row := rows[m.treeSelected]
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "q", "D":
+		return m, m.toggleTree()
+	case "up", "k":
+		return m, m.moveTree(rows, m.treeSelected-1)
+	case "down", "j":
+		return m, m.moveTree(rows, m.treeSelected+1)
+	case "g", "home":
+		return m, m.moveTree(rows, 0)
+	case "G", "end":
+		return m, m.moveTree(rows, len(rows)-1)
+	case "right", "l":
+		if row.session < 0 {
+			if m.treeExpanded[row.key] {
+				return m, m.moveTree(rows, m.treeSelected+1)
+			}
+			m.treeExpanded[row.key] = true
+		}
+	case "left", "h":
+		if row.session < 0 && m.treeExpanded[row.key] {
+			m.treeExpanded[row.key] = false
+			return m, nil
+		}
+		// Otherwise go up to the parent node
+		for i := m.treeSelected - 1; i >= 0; i-- {
+			if rows[i].depth < row.depth {
+				return m, m.moveTree(rows, i)
+			}
+		}
+	case "enter", " ":
+		if row.session < 0 {
+			m.treeExpanded[row.key] = !m.treeExpanded[row.key]
+			return m, nil
+		}
+		// Back to the flat list on the chosen session
+		m.selected = row.session
+		m.showTree = false
+		m.ensureVisible()
+		return m, m.loadFullSession(m.filteredSessions[m.selected].FilePath)
+	}
+	return m, nil
+}
+
+// moveTree selects row i of the tree, clamped, previewing it when it is a
+// session
+func (m *Model) moveTree(rows []treeRow, i int) tea.Cmd {
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(rows) {
+		i = len(rows) - 1
+	}
+	m.treeSelected = i
+	if rows[i].session < 0 || rows[i].session == m.selected {
+		return nil
+	}
+	m.selected = rows[i].session
+	return m.previewSelected()
+}
+
+// renderTree draws the date tree in the list pane
+func (m *Model) renderTree(width, height int) string {
+	innerHeight := height - 5
+	innerWidth := width - 4
+	if innerHeight < 1 || innerWidth < 1 {
+		return sessionListStyle.Width(width).Height(height).Render("")
+	}
+
+	lines := []string{titleStyle.Render("Sessions by Date"), ""}
+	rows := m.treeRows()
+	if m.treeSelected >= len(rows) {
+		m.treeSelected = len(rows) - 1
+	}
+	if m.treeSelected < 0 {
+		m.treeSelected = 0
+	}
+
+	itemsHeight := innerHeight - 2
+	if itemsHeight < 1 {
+		itemsHeight = 1
+	}
+	if m.treeSelected < m.treeScroll {
+		m.treeScroll = m.treeSelected
+	} else if m.treeSelected >= m.treeScroll+itemsHeight {
+		m.treeScroll = m.treeSelected - itemsHeight + 1
+	}
+	if maxScroll := len(rows) - itemsHeight; m.treeScroll > maxScroll {
+		m.treeScroll = max(maxScroll, 0)
+	}
+	end := min(m.treeScroll+itemsHeight, len(rows))
+
+	for i := m.treeScroll; i < end; i++ {
+		row := rows[i]
+		indent := strings.Repeat("  ", row.depth)
+		var line string
+		if row.session < 0 {
+			glyph := "▸ "
+			if m.treeExpanded[row.key] {
+				glyph = "▾ "
+			}
+			count := fmt.Sprintf(" (%d)", row.count)
+			label := truncateRunes(row.label, innerWidth-utf8.RuneCountInString(indent+glyph+count))
+			line = indent + glyph + label + count
+		} else {
+			line = truncateRunes(indent+row.label, innerWidth)
+		}
+
+		if i == m.treeSelected {
+			line = selectedItemStyle.Render(line)
+		} else {
+			line = sessionItemStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	for len(lines) < innerHeight {
+		lines = append(lines, "")
+	}
+	return sessionListStyle.Width(width).Height(height).Render(strings.Join(lines, "\n"))
+}
@@ -3,23 +3,82 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/gitrepo"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+	"github.com/davidpaquet/claude-session-browser/internal/server"
 	"github.com/davidpaquet/claude-session-browser/internal/ui"
+	"github.com/muesli/termenv"
 )
 
 const version = "v0.2.0"
 
+// currentSessionEnv names the variables that may hold the ID of the Claude
+// session this browser was started from, in order of preference
+var currentSessionEnv = []string{"CLAUDE_SESSION_ID", "CLAUDE_CODE_SESSION_ID"}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+	
 	// Parse command line flags
 	var claudeDir string
 	flag.StringVar(&claudeDir, "claude-dir", "", "Claude projects directory (default: ~/.claude/projects)")
 	flag.StringVar(&claudeDir, "d", "", "Claude projects directory (shorthand)")
 	
+	var projectName string
+	flag.StringVar(&projectName, "project", "", "Open this project: its name, path or the end of its path (e.g. myapp)")
+	
+	var defaultProjectFlag string
+	flag.StringVar(&defaultProjectFlag, "default-project", "cwd", "Project to open without --project: cwd, recent, first or none (all-projects table)")
+	
+	var initialSearch string
+	flag.StringVar(&initialSearch, "search", "", "Start with the results of this content search")
+	
+	var configPath string
+	flag.StringVar(&configPath, "config", config.DefaultPath(), "Config file path")
+	
+	searchRecent := -1
+	flag.IntVar(&searchRecent, "search-recent", -1, "Search only the N most recently active sessions (0 for all)")
+	
+	var searchBackend string
+	flag.StringVar(&searchBackend, "search-backend", "", "Content search backend: rg, go or auto (default from config, auto)")
+	
+	var noIndex bool
+	flag.BoolVar(&noIndex, "no-index", false, "Search session files on disk instead of keeping their content in memory")
+	
+	var pick bool
+	flag.BoolVar(&pick, "pick", false, "Choose a session and print it to stdout")
+	
+	var pickOutput string
+	flag.StringVar(&pickOutput, "pick-output", "", "What --pick prints: id or path (default from config, id)")
+	
+	var pipeCommand string
+	flag.StringVar(&pipeCommand, "pipe-command", "", "Command that | pipes the selected session into (e.g. \"less\")")
+	
+	var hideCost bool
+	flag.BoolVar(&hideCost, "hide-cost", false, "Never show session costs")
+	
+	var serve bool
+	flag.BoolVar(&serve, "serve", false, "Serve sessions as HTML pages for a web browser instead of starting the TUI")
+	
+	var serveAddr string
+	flag.StringVar(&serveAddr, "serve-addr", server.DefaultAddr, "Address --serve listens on")
+	
+	var debug bool
+	flag.BoolVar(&debug, "debug", false, "Write diagnostic logs to debug.log")
+	
 	var help bool
 	flag.BoolVar(&help, "help", false, "Show help")
 	flag.BoolVar(&help, "h", false, "Show help (shorthand)")
@@ -32,58 +91,190 @@ func main() {
 		os.Exit(0)
 	}
 	
-	// Set Claude directory
-	if claudeDir == "" {
-		claudeDir = os.Getenv("CLAUDE_DIR")
+	// Load user preferences
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
 	}
-	if claudeDir == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			log.Fatal("Failed to get home directory:", err)
+	if searchRecent >= 0 {
+		cfg.Search.Recent = searchRecent
+	}
+	if searchBackend != "" {
+		cfg.Search.Backend = searchBackend
+	}
+	if _, err := search.ParseBackend(cfg.Search.Backend); err != nil {
+		log.Fatal(err)
+	}
+	if noIndex {
+		cfg.Search.Index = false
+	}
+	if pipeCommand != "" {
+		cfg.Pipe.Command = pipeCommand
+	}
+	if hideCost {
+		cfg.Cost.Display = "hide"
+	}
+	switch cfg.Cost.Display {
+	case "auto", "show", "hide":
+	default:
+		log.Fatalf("Invalid cost display %q: want auto, show or hide", cfg.Cost.Display)
+	}
+	switch cfg.Copy.Feedback {
+	case "timed", "instant", "badge":
+	default:
+		log.Fatalf("Invalid copy feedback %q: want timed, instant or badge", cfg.Copy.Feedback)
+	}
+	switch cfg.Search.Enter {
+	case "results", "copy":
+	default:
+		log.Fatalf("Invalid search enter %q: want results or copy", cfg.Search.Enter)
+	}
+	switch cfg.Details.View {
+	case "both", "parsed":
+	default:
+		log.Fatalf("Invalid details view %q: want both or parsed", cfg.Details.View)
+	}
+	if pickOutput != "" {
+		cfg.Pick.Output = pickOutput
+	}
+	if cfg.Pick.Output != "id" && cfg.Pick.Output != "path" {
+		log.Fatalf("Invalid pick output %q: want id or path", cfg.Pick.Output)
+	}
+	if !slices.Contains(defaultProjectStrategies, defaultProjectFlag) {
+		log.Fatalf("Invalid default project %q: want %s", defaultProjectFlag, strings.Join(defaultProjectStrategies, ", "))
+	}
+	
+	// Set Claude directory
+	claudeDir = resolveClaudeDir(claudeDir)
+	
+	// A session file is browsed on its own, as a list of one, with the
+	// directory above its project as the projects root
+	rootDir := claudeDir
+	sessionFile := parser.IsSessionFile(claudeDir)
+	if sessionFile {
+		if abs, err := filepath.Abs(claudeDir); err == nil {
+			claudeDir = abs
 		}
-		claudeDir = filepath.Join(home, ".claude", "projects")
+		rootDir = filepath.Dir(filepath.Dir(claudeDir))
+		if serve || projectName != "" {
+			log.Fatalf("%s is a session file; --serve and --project need a projects directory", claudeDir)
+		}
+	} else if info, err := os.Stat(claudeDir); err == nil && !info.IsDir() {
+		log.Fatalf("%s is a file, not a directory: pass a Claude projects directory or a session .jsonl file", claudeDir)
 	}
 	
 	// Set CLAUDE_DIR environment variable for the app
-	os.Setenv("CLAUDE_DIR", claudeDir)
+	os.Setenv("CLAUDE_DIR", rootDir)
+	
+	if serve {
+		os.Exit(runServer(claudeDir, serveAddr, cfg))
+	}
 	
-	// Get current working directory and convert to Claude path format
 	cwd, _ := os.Getwd()
-	claudePath := convertToClaudePath(cwd)
 	
-	// Check if this project exists in the Claude directory
-	projectPath := filepath.Join(claudeDir, claudePath)
-	if _, err := os.Stat(projectPath); err == nil && hasJSONLFiles(projectPath) {
-		// Found matching project for current directory
-		claudeDir = projectPath
+	startInTable := false
+	if projectName != "" {
+		// Asked for by name: open it or explain why not, never a fallback
+		path, err := findProject(claudeDir, projectName)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		claudeDir = path
+	} else if !sessionFile {
+		// A session file is the whole list, with no project to pick
+		if path, ok := defaultProject(claudeDir, defaultProjectFlag, cwd); ok {
+			claudeDir = path
+		} else {
+			// No single project to open: "none" asks for all of them
+			startInTable = defaultProjectFlag == "none"
+		}
+	}
+	
+	// The TUI owns the terminal, so diagnostics go to a file or nowhere
+	if debug {
+		logFile, err := tea.LogToFile("debug.log", "debug")
+		if err != nil {
+			log.Fatal("Failed to open debug log:", err)
+		}
+		defer logFile.Close()
 	} else {
-		// No match for current directory, just use first project with JSONL files
-		entries, err := os.ReadDir(claudeDir)
-		if err == nil {
-			for _, entry := range entries {
-				if entry.IsDir() {
-					testPath := filepath.Join(claudeDir, entry.Name())
-					if hasJSONLFiles(testPath) {
-						claudeDir = testPath
-						break
-					}
-				}
-			}
+		log.SetOutput(io.Discard)
+	}
+	
+	app := ui.NewApp(claudeDir, version, cfg)
+	if repo, ok := gitrepo.Find(cwd); ok {
+		app.SetRepo(repo)
+	}
+	
+	if initialSearch != "" {
+		app.SetInitialSearch(initialSearch)
+	}
+	if startInTable {
+		app.StartInTable()
+	}
+	
+	// Launched from inside a Claude session: start on it
+	for _, name := range currentSessionEnv {
+		if id := os.Getenv(name); id != "" {
+			app.SetCurrentSession(id)
+			break
 		}
 	}
 	
-	app := ui.NewApp(claudeDir, version)
+	opts := []tea.ProgramOption{
+		tea.WithAltScreen(), // Use alternate screen buffer
+	}
+	if cfg.List.RefreshOnFocus {
+		opts = append(opts, tea.WithReportFocus())
+	}
+	if pick {
+		// Keep stdout for the result, so $(...) captures only the choice
+		app.EnablePickMode()
+		lipgloss.DefaultRenderer().SetOutput(termenv.NewOutput(os.Stderr))
+		opts = append(opts, tea.WithOutput(os.Stderr))
+	}
 	
 	// Create the Bubble Tea program
-	p := tea.NewProgram(
-		app,
-		tea.WithAltScreen(), // Use alternate screen buffer
-	)
+	p := tea.NewProgram(app, opts...)
 	
 	// Run the program
-	if _, err := p.Run(); err != nil {
+	_, err = p.Run()
+	log.SetOutput(os.Stderr)
+	if err != nil {
 		log.Fatal("Error running program:", err)
 	}
+	if err := app.SaveState(); err != nil {
+		log.Printf("Could not save viewed sessions: %v", err)
+	}
+	
+	if pick {
+		session, ok := app.Picked()
+		if !ok {
+			os.Exit(1)
+		}
+		if cfg.Pick.Output == "path" {
+			fmt.Println(session.FilePath)
+		} else {
+			fmt.Println(session.ID)
+		}
+	}
+}
+
+// resolveClaudeDir picks the Claude projects directory: the flag value,
+// else $CLAUDE_DIR, else ~/.claude/projects
+func resolveClaudeDir(claudeDir string) string {
+	if claudeDir == "" {
+		claudeDir = os.Getenv("CLAUDE_DIR")
+	}
+	if claudeDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			log.Fatal("Failed to get home directory:", err)
+		}
+		claudeDir = filepath.Join(home, ".claude", "projects")
+	}
+	return claudeDir
 }
 
 func convertToClaudePath(path string) string {
@@ -117,27 +308,96 @@ A terminal user interface for browsing and resuming Claude Code sessions.
 
 Usage:
   claude-session-browser [options]
+  claude-session-browser import [options] FILE   (see import --help)
 
 Options:
-  -d, --claude-dir PATH    Claude projects directory (default: ~/.claude/projects)
+  -d, --claude-dir PATH    Claude projects directory (default: ~/.claude/projects),
+                           or a session .jsonl file to browse on its own
+  --project NAME           Open this project instead of the current directory's:
+                           its path, directory name or the end of its path (myapp)
+  --default-project HOW    Project to open without --project: cwd (default, the
+                           current directory's, else the first), recent (most
+                           recently active), first, or none (all-projects table)
+  --search QUERY           Start on the results of this content search
+  --config PATH            Config file (default: ~/.config/claude-session-browser/config.json)
+  --search-recent N        Content search covers only the N latest sessions (Ctrl+R toggles)
+  --search-backend NAME    Content search with rg, go (built in) or auto (default:
+                           ripgrep when installed, else go)
+  --no-index               Search session files on disk each time instead of keeping
+                           their content in memory (search.index)
+  --pick                   Choose a session and print its ID to stdout, then exit
+                           (exit status 1 when cancelled)
+  --pick-output id|path    Print the session ID or session file path with --pick
+  --pipe-command CMD       Command that | feeds the selected session to on stdin
+  --hide-cost              Hide session costs (shown by default when any session has one)
+  --serve                  Serve every project's sessions as HTML pages with content
+                           search, for reading in a web browser, instead of the TUI
+  --serve-addr ADDR        Address --serve listens on (default: 127.0.0.1:7878,
+                           this machine only)
+  --debug                  Write diagnostic logs to debug.log in the current directory
   -h, --help              Show this help message
 
 Environment Variables:
   CLAUDE_DIR              Alternative way to set Claude projects directory
+  CLAUDE_SESSION_ID       Session to start on and mark "current" (set when
+                          launched from inside a Claude session)
 
 Keyboard Shortcuts:
   ↑/↓, j/k               Navigate sessions
-  Enter                  Copy resume command to clipboard
+  Enter                  Copy resume command to clipboard (at the first listed
+                         search match with copy.matchTemplate)
+  C                      Copy a resume command that compacts the context (copy.compactTemplate)
+  12 Enter               Jump to the 12th session in the list (also :12)
+  c                      Copy project path
+  J                      Copy the last message's full JSON
+  x                      Show the last few raw messages in the details pane, or just the last
+  X                      Switch the details pane to the raw JSON alone, scrolled with PgUp/PgDn
+  M                      Copy a Markdown summary for issues and PRs
+  L                      Copy a link to the session (copy.linkTemplate)
+  N                      Add or edit a note on the session (Ctrl+S saves)
+  v                      View transcript (opens at the first search match;
+                         Enter unfolds tool calls)
+  /                      Search session content
+  f                      Filter sessions by title or ID (fuzzy, no ripgrep needed)
+  t                      Cycle list label: ID, title, last-message preview
+  o                      Cycle sort order: most recent, title, cost, messages, recently viewed
+  R                      Toggle recently resumed sessions
+  e                      Hide/show sessions without messages
+  d                      Show sessions from today, the last 7 days, the last 30 days or all dates
+  s                      Show/hide session file sizes
+  w                      Export session to HTML/gist and copy its path or URL
+  Space                  Mark/unmark session
+  W                      Export marked sessions into one Markdown document
+  |                      Pipe the session transcript to the --pipe-command
+  Y                      Copy the session's plain-text transcript
+  T                      All-projects table (1-5 sort by column, h home only)
+  D                      Sessions as a year/month/day tree (←/→ collapse/expand)
+  p                      Switch project (type to fuzzy-filter, Ctrl+O home only, Ctrl+S sort)
+  Ctrl+G                 Toggle sessions run anywhere in the current git repo
+  A                      Toggle sessions from every project in one list
+  :, Ctrl+P              Command palette (fuzzy-filter all actions)
+  Ctrl+T                 Toggle case-sensitive search (while searching)
+  Ctrl+R                 Toggle searching only the latest sessions (while searching)
+  Ctrl+S                 Cycle search scope: this project, startup project, all projects
+  +/-                    Show more/fewer matches in the details pane (search results)
+  [/], g/G               Scroll the details pane's matches, jump to the first/last
   r                      Refresh session list
+  U                      Rescan the selected session's file, even if its time is unchanged
   q                      Quit
 
 Examples:
   # Run with default directory
   claude-session-browser
 
+  # Open a project's sessions about a topic
+  claude-session-browser --project myapp --search "auth bug"
+  
   # Specify custom Claude directory
   claude-session-browser --claude-dir ~/my-claude-projects
 
+  # Import a conversation exported from another assistant into this project
+  claude-session-browser import chat.json
+  
   # Use environment variable
   export CLAUDE_DIR=~/my-claude-projects
   claude-session-browser`)
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/davidpaquet/claude-session-browser/internal/model"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+)
+
+// maxProjectSuggestions caps the close matches listed for an unknown project
+const maxProjectSuggestions = 5
+
+// findProject resolves --project to a project directory under rootDir. name
+// may be the encoded directory name, a path (absolute, ~/ or relative to
+// the current directory), or the trailing part of one such as "myapp" or
+// "Projects/myapp".
+func findProject(rootDir, name string) (string, error) {
+	projects, err := parser.NewParser().ListProjects(rootDir)
+	if err != nil {
+		return "", fmt.Errorf("listing projects in %s: %w", rootDir, err)
+	}
+
+	// A path names exactly one project
+	if path := expandPath(name); filepath.IsAbs(path) || strings.HasPrefix(name, ".") {
+		if abs, err := filepath.Abs(path); err == nil {
+			encoded := convertToClaudePath(abs)
+			for _, project := range projects {
+				if project.Name == encoded {
+					return project.Path, nil
+				}
+			}
+		}
+	}
+
+	suffix := "-" + strings.Trim(strings.ReplaceAll(name, string(filepath.Separator), "-"), "-")
+	var matches []model.ProjectInfo
+	for _, project := range projects {
+		if project.Name == name {
+			return project.Path, nil
+		}
+		if strings.HasSuffix(project.Name, suffix) {
+			matches = append(matches, project)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0].Path, nil
+	case 0:
+		return "", fmt.Errorf("no project named %q in %s%s", name, rootDir, suggestProjects(projects, name))
+	}
+	paths := make([]string, len(matches))
+	for i, project := range matches {
+		paths[i] = "  " + project.DecodedPath()
+	}
+	return "", fmt.Errorf("project %q is ambiguous, it could be:\n%s", name, strings.Join(paths, "\n"))
+}
+
+// defaultProjectStrategies are the values --default-project takes
+var defaultProjectStrategies = []string{"cwd", "recent", "first", "none"}
+
+// defaultProject picks the project to open when --project is not given:
+// "cwd" the current directory's project, falling back to "first"; "recent"
+// the most recently active project; "first" the first project holding
+// sessions. It reports false when none applies, as always for "none".
+func defaultProject(rootDir, strategy, cwd string) (string, bool) {
+	switch strategy {
+	case "cwd":
+		if path := filepath.Join(rootDir, convertToClaudePath(cwd)); hasJSONLFiles(path) {
+			return path, true
+		}
+		return firstProject(rootDir)
+	case "recent":
+		projects, err := parser.NewParser().ListProjects(rootDir)
+		if err != nil || len(projects) == 0 {
+			return "", false
+		}
+		return projects[0].Path, true
+	case "first":
+		return firstProject(rootDir)
+	}
+	return "", false
+}
+
+// firstProject returns the first project directory, in name order, that
+// holds any session files
+func firstProject(rootDir string) (string, bool) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		return "", false
+	}
+	for _, entry := range entries {
+		if path := filepath.Join(rootDir, entry.Name()); entry.IsDir() && hasJSONLFiles(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// suggestProjects lists the projects whose paths fuzzy-match name, for the
+// error about an unknown one
+func suggestProjects(projects []model.ProjectInfo, name string) string {
+	paths := make([]string, len(projects))
+	for i, project := range projects {
+		paths[i] = model.ShortenHome(project.DecodedPath())
+	}
+
+	results := search.NewFilterEngine().FilterText(name, paths)
+	if len(results) == 0 {
+		return ""
+	}
+	lines := []string{"; close matches:"}
+	for i, result := range results {
+		if i == maxProjectSuggestions {
+			break
+		}
+		lines = append(lines, "  "+paths[result.SessionIndex])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// expandPath replaces a leading ~ with the home directory
+func expandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
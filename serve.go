@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/davidpaquet/claude-session-browser/internal/config"
+	"github.com/davidpaquet/claude-session-browser/internal/parser"
+	"github.com/davidpaquet/claude-session-browser/internal/search"
+	"github.com/davidpaquet/claude-session-browser/internal/server"
+)
+
+// runServer implements --serve, serving every project under rootDir as
+// HTML pages until interrupted. It returns the exit status.
+func runServer(rootDir, addr string, cfg *config.Config) int {
+	backend, _ := search.ParseBackend(cfg.Search.Backend)
+	p := parser.NewParserWithOptions(parser.Options{
+		Fields:             parser.Fields(cfg.Fields),
+		Summary:            parser.SummaryOptions(cfg.Summary),
+		MergeContinuations: cfg.List.MergeContinuations,
+		Ellipsis:           cfg.Theme.Ellipsis,
+		ExcludeProjects:    cfg.Projects.Exclude,
+		TitleMessages:      cfg.List.TitleMessages,
+	})
+	srv := server.New(rootDir, p, backend, server.Options{
+		IgnoreCase: cfg.Search.IgnoreCase,
+		LocalOnly:  server.IsLoopback(addr),
+	})
+
+	// Listen first so the printed URL is live, and reports the real port
+	// when addr asks for any free one (":0")
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !server.IsLoopback(addr) {
+		fmt.Fprintf(os.Stderr, "Warning: %s accepts connections from other machines, and session transcripts can hold secrets\n", addr)
+	}
+	fmt.Printf("Serving %s at http://%s/ (Ctrl+C to stop)\n", rootDir, listener.Addr())
+
+	if err := http.Serve(listener, srv.Handler()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}